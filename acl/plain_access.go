@@ -0,0 +1,199 @@
+package acl
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"go.uber.org/zap"
+	. "rocketmq-go/common"
+	"rocketmq-go/common/clock"
+	pb "rocketmq-go/common/proto"
+	. "rocketmq-go/logging"
+	"rocketmq-go/remote"
+	"rocketmq-go/remote/native"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	RoleReadOnly = "readonly"
+	RoleBroker   = "broker"
+	RoleAdmin    = "admin"
+)
+
+var (
+	ErrAccessDenied = errors.New("access denied")
+
+	readOnlyCodes = []pb.RequestCode{
+		pb.RequestCode_GET_KV_CONFIG,
+		pb.RequestCode_GET_ROUTEINFO_BY_TOPIC,
+		pb.RequestCode_GET_BROKER_CLUSTER_INFO,
+		pb.RequestCode_GET_ALL_TOPIC_LIST_FROM_NAMESERVER,
+		pb.RequestCode_GET_KVLIST_BY_NAMESPACE,
+		pb.RequestCode_GET_TOPICS_BY_CLUSTER,
+		pb.RequestCode_GET_SYSTEM_TOPIC_LIST_FROM_NS,
+		pb.RequestCode_GET_UNIT_TOPIC_LIST,
+		pb.RequestCode_GET_HAS_UNIT_SUB_TOPIC_LIST,
+		pb.RequestCode_GET_HAS_UNIT_SUB_UNUNIT_TOPIC_LIST,
+		pb.RequestCode_SUBSCRIBE_TOPIC_ROUTE,
+		pb.RequestCode_UNSUBSCRIBE_TOPIC_ROUTE,
+	}
+	brokerCodes = []pb.RequestCode{
+		pb.RequestCode_REGISTER_BROKER,
+		pb.RequestCode_UNREGISTER_BROKER,
+		pb.RequestCode_QUERY_DATA_VERSION,
+	}
+
+	// Request codes each role may send, admin may send all of them
+	rolePermissions = map[string]map[int32]bool{
+		RoleReadOnly: codeSet(readOnlyCodes),
+		RoleBroker:   codeSet(readOnlyCodes, brokerCodes),
+	}
+)
+
+func codeSet(codeLists ...[]pb.RequestCode) map[int32]bool {
+	set := make(map[int32]bool)
+	for _, codes := range codeLists {
+		for _, code := range codes {
+			set[int32(code)] = true
+		}
+	}
+	return set
+}
+
+type Account struct {
+	AccessKey string `toml:"accessKey"`
+	SecretKey string `toml:"secretKey"`
+	Role      string `toml:"role"`
+}
+
+type plainAccessConfig struct {
+	Accounts []Account `toml:"accounts"`
+}
+
+// PlainAccessValidator checks the signature and the role of requests
+// against the accounts of a plain access config file.
+type PlainAccessValidator struct {
+	rw       sync.RWMutex
+	accounts map[string]Account
+	clock    clock.Clock
+}
+
+func NewPlainAccessValidator(path string) (*PlainAccessValidator, error) {
+	v := &PlainAccessValidator{clock: clock.Real}
+	if err := v.Load(path); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Load replaces the accounts with the ones of the config file at path.
+func (v *PlainAccessValidator) Load(path string) error {
+	var conf plainAccessConfig
+	if _, err := toml.DecodeFile(path, &conf); err != nil {
+		return err
+	}
+
+	accounts := make(map[string]Account, len(conf.Accounts))
+	for _, account := range conf.Accounts {
+		if account.AccessKey == "" || account.SecretKey == "" {
+			return fmt.Errorf("account without access key or secret key in %s", path)
+		}
+		if _, ok := rolePermissions[account.Role]; !ok && account.Role != RoleAdmin {
+			return fmt.Errorf("unknown role %q of access key %s", account.Role, account.AccessKey)
+		}
+		if _, ok := accounts[account.AccessKey]; ok {
+			return fmt.Errorf("duplicate access key %s in %s", account.AccessKey, path)
+		}
+		accounts[account.AccessKey] = account
+	}
+
+	v.rw.Lock()
+	v.accounts = accounts
+	v.rw.Unlock()
+	return nil
+}
+
+// SetClock changes the clock the timestamps of requests are checked with.
+func (v *PlainAccessValidator) SetClock(c clock.Clock) {
+	v.rw.Lock()
+	defer v.rw.Unlock()
+
+	v.clock = c
+}
+
+// Validate returns an error wrapping ErrAccessDenied when cmd is not signed
+// by a known account within MaxClockSkew or its role may not send the
+// request code.
+func (v *PlainAccessValidator) Validate(cmd *pb.RemoteCommand) error {
+	return v.validate(cmd, false)
+}
+
+// ValidateJava is Validate for the requests of java clients on the native
+// listener, signed with CalJavaSignature. Their signatures carry no
+// timestamp, so a replayed one is not told apart.
+func (v *PlainAccessValidator) ValidateJava(cmd *pb.RemoteCommand) error {
+	return v.validate(cmd, true)
+}
+
+func (v *PlainAccessValidator) validate(cmd *pb.RemoteCommand, java bool) error {
+	accessKey := cmd.ExtFields[AccessKey]
+	if accessKey == "" {
+		return fmt.Errorf("%w: no access key", ErrAccessDenied)
+	}
+
+	v.rw.RLock()
+	account, ok := v.accounts[accessKey]
+	now := v.clock.Now()
+	v.rw.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: unknown access key %s", ErrAccessDenied, accessKey)
+	}
+
+	calSignature := CalSignature
+	if java {
+		calSignature = CalJavaSignature
+	}
+	signature := calSignature(cmd, account.SecretKey)
+	if !hmac.Equal([]byte(signature), []byte(cmd.ExtFields[Signature])) {
+		return fmt.Errorf("%w: signature mismatch", ErrAccessDenied)
+	}
+
+	// A signed request replayed later is stale
+	if !java {
+		mills, err := strconv.ParseInt(cmd.ExtFields[Timestamp], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: no timestamp", ErrAccessDenied)
+		}
+		skew := now.Sub(time.Unix(0, mills*int64(time.Millisecond)))
+		if skew > MaxClockSkew || skew < -MaxClockSkew {
+			return fmt.Errorf("%w: stale timestamp %d", ErrAccessDenied, mills)
+		}
+	}
+
+	if account.Role != RoleAdmin && !rolePermissions[account.Role][cmd.Code] {
+		return fmt.Errorf("%w: role %s can not send request code %d", ErrAccessDenied, account.Role, cmd.Code)
+	}
+	return nil
+}
+
+// Interceptor answers ACCESS_DENIED to the requests failing Validate, or
+// ValidateJava on the native listener, and writes them to the audit log.
+func (v *PlainAccessValidator) Interceptor() remote.Interceptor {
+	return func(next remote.Processor) remote.Processor {
+		return func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+			if err := v.validate(request, native.IsNative(ctx)); err != nil {
+				AuditLog.Warn("Request denied",
+					zap.String("addr", GetRemoteAddr(ctx)),
+					zap.String("accessKey", request.ExtFields[AccessKey]),
+					zap.Int32("code", request.Code),
+					zap.Error(err))
+				return remote.CreateResponseCommand(pb.ResponseCode_ACCESS_DENIED, err.Error())
+			}
+			return next(ctx, request)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package acl
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"rocketmq-go/common/clock"
+	pb "rocketmq-go/common/proto"
+	"rocketmq-go/remote"
+	"testing"
+	"time"
+)
+
+const testConfig = `
+[[accounts]]
+accessKey = "broker"
+secretKey = "broker-secret"
+role = "broker"
+
+[[accounts]]
+accessKey = "admin"
+secretKey = "admin-secret"
+role = "admin"
+
+[[accounts]]
+accessKey = "client"
+secretKey = "client-secret"
+role = "readonly"
+`
+
+func newTestValidator(t *testing.T) *PlainAccessValidator {
+	dir, err := ioutil.TempDir("", "acl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "plain_acl.toml")
+	if err := ioutil.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewPlainAccessValidator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func signed(code pb.RequestCode, accessKey string, secretKey string) *pb.RemoteCommand {
+	cmd := &pb.RemoteCommand{
+		Code:   int32(code),
+		Header: []byte("header"),
+		Body:   []byte("body"),
+	}
+	Sign(cmd, accessKey, secretKey)
+	return cmd
+}
+
+func TestValidate(t *testing.T) {
+	v := newTestValidator(t)
+
+	tampered := signed(pb.RequestCode_GET_KV_CONFIG, "client", "client-secret")
+	tampered.Body = []byte("other body")
+	// A read signature may not be replayed as a write
+	otherCode := signed(pb.RequestCode_GET_KV_CONFIG, "admin", "admin-secret")
+	otherCode.Code = int32(pb.RequestCode_DELETE_KV_CONFIG)
+	noTimestamp := signed(pb.RequestCode_GET_KV_CONFIG, "client", "client-secret")
+	delete(noTimestamp.ExtFields, Timestamp)
+	noTimestamp.ExtFields[Signature] = CalSignature(noTimestamp, "client-secret")
+
+	tests := []struct {
+		name    string
+		cmd     *pb.RemoteCommand
+		allowed bool
+	}{
+		{"unsigned", &pb.RemoteCommand{Code: int32(pb.RequestCode_GET_KV_CONFIG)}, false},
+		{"unknown access key", signed(pb.RequestCode_GET_KV_CONFIG, "nobody", "secret"), false},
+		{"wrong secret key", signed(pb.RequestCode_GET_KV_CONFIG, "client", "broker-secret"), false},
+		{"tampered body", tampered, false},
+		{"other code", otherCode, false},
+		{"no timestamp", noTimestamp, false},
+		{"readonly query", signed(pb.RequestCode_GET_ROUTEINFO_BY_TOPIC, "client", "client-secret"), true},
+		{"readonly register", signed(pb.RequestCode_REGISTER_BROKER, "client", "client-secret"), false},
+		{"broker register", signed(pb.RequestCode_REGISTER_BROKER, "broker", "broker-secret"), true},
+		{"broker delete topic", signed(pb.RequestCode_DELETE_TOPIC_IN_NAMESRV, "broker", "broker-secret"), false},
+		{"admin delete topic", signed(pb.RequestCode_DELETE_TOPIC_IN_NAMESRV, "admin", "admin-secret"), true},
+		{"admin put kv config", signed(pb.RequestCode_PUT_KV_CONFIG, "admin", "admin-secret"), true},
+	}
+	for _, test := range tests {
+		err := v.Validate(test.cmd)
+		if test.allowed && err != nil {
+			t.Errorf("%s: denied: %v", test.name, err)
+		}
+		if !test.allowed && !errors.Is(err, ErrAccessDenied) {
+			t.Errorf("%s: err = %v, want ErrAccessDenied", test.name, err)
+		}
+	}
+}
+
+// javaSigned returns GET_ROUTEINFO_BY_TOPIC signed the way the
+// AclClientRPCHook of a java client signs it, the signature computed apart
+// with:
+//
+//	printf clientTopicTest | openssl dgst -sha1 -hmac client-secret -binary | base64
+func javaSigned() *pb.RemoteCommand {
+	return &pb.RemoteCommand{
+		Code: int32(pb.RequestCode_GET_ROUTEINFO_BY_TOPIC),
+		ExtFields: map[string]string{
+			AccessKey: "client",
+			Signature: "dDQVLKANzh9ALoxTEZZ7vFMtCY8=",
+			"topic":   "TopicTest",
+		},
+	}
+}
+
+func TestValidateJava(t *testing.T) {
+	v := newTestValidator(t)
+
+	if err := v.ValidateJava(javaSigned()); err != nil {
+		t.Fatalf("java signature denied: %v", err)
+	}
+	tampered := javaSigned()
+	tampered.ExtFields["topic"] = "TopicOther"
+	if err := v.ValidateJava(tampered); !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("tampered java request err = %v, want ErrAccessDenied", err)
+	}
+
+	// Neither signature passes for the other protocol
+	if err := v.Validate(javaSigned()); !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("java signature on grpc err = %v, want ErrAccessDenied", err)
+	}
+	if err := v.ValidateJava(signed(pb.RequestCode_GET_KV_CONFIG, "client", "client-secret")); !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("grpc signature on native err = %v, want ErrAccessDenied", err)
+	}
+}
+
+func TestValidateStaleTimestamp(t *testing.T) {
+	v := newTestValidator(t)
+	cmd := signed(pb.RequestCode_GET_KV_CONFIG, "client", "client-secret")
+
+	fake := clock.NewFake(time.Now())
+	v.SetClock(fake)
+	fake.Advance(MaxClockSkew - time.Second)
+	if err := v.Validate(cmd); err != nil {
+		t.Fatalf("denied within the clock skew: %v", err)
+	}
+	fake.Advance(2 * time.Second)
+	if err := v.Validate(cmd); !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("replayed request err = %v, want ErrAccessDenied", err)
+	}
+
+	v.SetClock(clock.NewFake(time.Now().Add(-MaxClockSkew - time.Second)))
+	if err := v.Validate(cmd); !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("request from the future err = %v, want ErrAccessDenied", err)
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	v := newTestValidator(t)
+	p := remote.Chain(func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		return remote.CreateResponseCommand(pb.ResponseCode_SUCCESS, "")
+	}, v.Interceptor())
+
+	response := p(context.Background(), signed(pb.RequestCode_UNREGISTER_BROKER, "client", "client-secret"))
+	if response.Code != int32(pb.ResponseCode_ACCESS_DENIED) {
+		t.Fatalf("code = %d, want ACCESS_DENIED", response.Code)
+	}
+
+	response = p(context.Background(), signed(pb.RequestCode_UNREGISTER_BROKER, "broker", "broker-secret"))
+	if response.Code != int32(pb.ResponseCode_SUCCESS) {
+		t.Fatalf("code = %d, want SUCCESS, remark: %s", response.Code, response.Remark)
+	}
+}
@@ -0,0 +1,77 @@
+package acl
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	pb "rocketmq-go/common/proto"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	AccessKey = "AccessKey"
+	Signature = "Signature"
+	// Timestamp is the time of signing in milliseconds, requests signed
+	// longer than MaxClockSkew ago are stale
+	Timestamp = "Timestamp"
+
+	MaxClockSkew = 5 * time.Minute
+)
+
+// CalSignature signs the request code, the ext fields but Signature in key
+// order, then the header and the body, with HmacSHA1.
+func CalSignature(cmd *pb.RemoteCommand, secretKey string) string {
+	mac := hmac.New(sha1.New, []byte(secretKey))
+	mac.Write([]byte(strconv.Itoa(int(cmd.Code))))
+	writeExtFields(mac, cmd.ExtFields)
+	mac.Write(cmd.Header)
+	mac.Write(cmd.Body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// CalJavaSignature signs the way AclUtils of the java RocketMQ does: the
+// ext fields but Signature in key order, then the body, with HmacSHA1.
+// Java clients on the native listener sign this way, it covers neither the
+// request code nor a timestamp.
+func CalJavaSignature(cmd *pb.RemoteCommand, secretKey string) string {
+	mac := hmac.New(sha1.New, []byte(secretKey))
+	writeExtFields(mac, cmd.ExtFields)
+	mac.Write(cmd.Body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// writeExtFields writes the values of extFields but Signature in key order.
+func writeExtFields(w io.Writer, extFields map[string]string) {
+	keys := make([]string, 0, len(extFields))
+	for key := range extFields {
+		if key != Signature {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		_, _ = io.WriteString(w, extFields[key])
+	}
+}
+
+// Sign puts accessKey, the time and the signature of cmd into its ext
+// fields.
+func Sign(cmd *pb.RemoteCommand, accessKey string, secretKey string) {
+	if cmd.ExtFields == nil {
+		cmd.ExtFields = make(map[string]string)
+	}
+	cmd.ExtFields[AccessKey] = accessKey
+	cmd.ExtFields[Timestamp] = strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
+	cmd.ExtFields[Signature] = CalSignature(cmd, secretKey)
+}
+
+// Signer returns a hook signing every request of a remote.Client.
+func Signer(accessKey string, secretKey string) func(*pb.RemoteCommand) {
+	return func(cmd *pb.RemoteCommand) {
+		Sign(cmd, accessKey, secretKey)
+	}
+}
@@ -0,0 +1,58 @@
+// Package clock reads the time through an interface, so tests can move it
+// by hand with Fake instead of sleeping.
+package clock
+
+import (
+	"time"
+)
+
+// Clock tells the time and makes tickers.
+type Clock interface {
+	Now() time.Time
+	// NewTicker returns a ticker like time.NewTicker, d must be positive.
+	NewTicker(d time.Duration) Ticker
+	// After returns a channel receiving the time once d passed, like
+	// time.After.
+	After(d time.Duration) <-chan time.Time
+}
+
+// Ticker is a time.Ticker of a Clock.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real is the system clock.
+var Real Clock = realClock{}
+
+// Mills returns the time of c in milliseconds, like
+// common.CurrentTimeMills.
+func Mills(c Clock) int64 {
+	return c.Now().UnixNano() / 1e6
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(d)}
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
@@ -0,0 +1,128 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a Clock which only moves by Advance. Like the ones of time, its
+// tickers keep one tick and drop the others until it is received.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers map[*fakeTicker]bool
+	waiters []waiter
+	// changed is broadcast when waiters are added
+	changed *sync.Cond
+}
+
+// waiter is a channel of After waiting for the time at.
+type waiter struct {
+	at time.Time
+	c  chan time.Time
+}
+
+// NewFake returns a fake clock reading now.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{
+		now:     now,
+		tickers: make(map[*fakeTicker]bool),
+	}
+	f.changed = sync.NewCond(&f.mu)
+	return f
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t := &fakeTicker{
+		fake:   f,
+		period: d,
+		next:   f.now.Add(d),
+		c:      make(chan time.Time, 1),
+	}
+	f.tickers[t] = true
+	return t
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c := make(chan time.Time, 1)
+	if d <= 0 {
+		c <- f.now
+		return c
+	}
+	f.waiters = append(f.waiters, waiter{at: f.now.Add(d), c: c})
+	f.changed.Broadcast()
+	return c
+}
+
+// BlockUntil waits until n channels of After are waiting, e.g. for the code
+// under test to wait for the clock before Advance.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for len(f.waiters) < n {
+		f.changed.Wait()
+	}
+}
+
+// Advance moves the clock forward by d and fires the tickers and the
+// channels of After due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+	waiters := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.at.After(f.now) {
+			waiters = append(waiters, w)
+			continue
+		}
+		w.c <- w.at
+	}
+	f.waiters = waiters
+	for t := range f.tickers {
+		for !t.next.After(f.now) {
+			select {
+			case t.c <- t.next:
+			default:
+			}
+			t.next = t.next.Add(t.period)
+		}
+	}
+}
+
+type fakeTicker struct {
+	fake   *Fake
+	period time.Duration
+	next   time.Time
+	c      chan time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+
+	delete(t.fake.tickers, t)
+}
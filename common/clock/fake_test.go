@@ -0,0 +1,69 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeTicker(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	f := NewFake(start)
+	ticker := f.NewTicker(time.Second)
+
+	f.Advance(999 * time.Millisecond)
+	select {
+	case <-ticker.C():
+		t.Fatal("ticked before its period")
+	default:
+	}
+
+	// Like time.Ticker, the ticks not received are dropped
+	f.Advance(3 * time.Second)
+	if tick := <-ticker.C(); !tick.Equal(start.Add(time.Second)) {
+		t.Fatalf("tick = %v, want the first one", tick)
+	}
+	select {
+	case tick := <-ticker.C():
+		t.Fatalf("dropped tick %v received", tick)
+	default:
+	}
+	if now := f.Now(); !now.Equal(start.Add(3999 * time.Millisecond)) {
+		t.Fatalf("now = %v", now)
+	}
+
+	ticker.Stop()
+	f.Advance(time.Minute)
+	select {
+	case <-ticker.C():
+		t.Fatal("stopped ticker ticked")
+	default:
+	}
+}
+
+func TestFakeAfter(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	f := NewFake(start)
+
+	done := make(chan time.Time)
+	go func() {
+		done <- <-f.After(time.Second)
+	}()
+	f.BlockUntil(1)
+
+	f.Advance(999 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("fired before its time")
+	default:
+	}
+	f.Advance(time.Millisecond)
+	if fired := <-done; !fired.Equal(start.Add(time.Second)) {
+		t.Fatalf("fired at %v", fired)
+	}
+
+	select {
+	case <-f.After(0):
+	default:
+		t.Fatal("After(0) did not fire right away")
+	}
+}
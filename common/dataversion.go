@@ -1,9 +1,85 @@
 package common
 
-import "go.uber.org/atomic"
+import (
+	"encoding/json"
+	"go.uber.org/atomic"
+	pb "rocketmq-go/common/proto"
+)
 
 type DataVersion struct {
 	timestamp int64
 	counter atomic.Int64
 }
 
+type dataVersionJSON struct {
+	Timestamp int64 `json:"timestamp"`
+	Counter   int64 `json:"counter"`
+}
+
+func NewDataVersion() *DataVersion {
+	return &DataVersion{timestamp: CurrentTimeMills()}
+}
+
+func NewDataVersionFromProto(v *pb.DataVersion) *DataVersion {
+	d := &DataVersion{}
+	if v != nil {
+		d.timestamp = v.Timestamp
+		d.counter.Store(v.Counter)
+	}
+	return d
+}
+
+func (d *DataVersion) GetTimestamp() int64 {
+	return d.timestamp
+}
+
+func (d *DataVersion) GetCounter() int64 {
+	return d.counter.Load()
+}
+
+// AssignNewOne copies timestamp and counter of other into d.
+func (d *DataVersion) AssignNewOne(other *DataVersion) {
+	d.timestamp = other.timestamp
+	d.counter.Store(other.counter.Load())
+}
+
+// NextVersion bumps the counter and refreshes the timestamp, it should be
+// called every time the data guarded by this version changes.
+func (d *DataVersion) NextVersion() {
+	d.timestamp = CurrentTimeMills()
+	d.counter.Inc()
+}
+
+func (d *DataVersion) Equals(other *DataVersion) bool {
+	if d == other {
+		return true
+	}
+	if d == nil || other == nil {
+		return false
+	}
+	return d.timestamp == other.timestamp && d.counter.Load() == other.counter.Load()
+}
+
+func (d *DataVersion) ToProto() *pb.DataVersion {
+	return &pb.DataVersion{
+		Timestamp: d.timestamp,
+		Counter:   d.counter.Load(),
+	}
+}
+
+func (d *DataVersion) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dataVersionJSON{
+		Timestamp: d.timestamp,
+		Counter:   d.counter.Load(),
+	})
+}
+
+func (d *DataVersion) UnmarshalJSON(data []byte) error {
+	var v dataVersionJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	d.timestamp = v.Timestamp
+	d.counter.Store(v.Counter)
+	return nil
+}
@@ -0,0 +1,46 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDataVersionNextVersion(t *testing.T) {
+	d := NewDataVersion()
+	other := &DataVersion{}
+	other.AssignNewOne(d)
+	if !d.Equals(other) {
+		t.Fatalf("assigned version should be equal")
+	}
+
+	other.NextVersion()
+	if d.Equals(other) {
+		t.Fatalf("next version should not be equal")
+	}
+	if other.GetCounter() != d.GetCounter()+1 {
+		t.Fatalf("counter = %d, want %d", other.GetCounter(), d.GetCounter()+1)
+	}
+}
+
+func TestDataVersionEncoding(t *testing.T) {
+	d := NewDataVersion()
+	d.NextVersion()
+	d.NextVersion()
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON := &DataVersion{}
+	if err := json.Unmarshal(data, fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !d.Equals(fromJSON) {
+		t.Fatalf("json round trip mismatch: %s", data)
+	}
+
+	fromProto := NewDataVersionFromProto(d.ToProto())
+	if !d.Equals(fromProto) {
+		t.Fatalf("proto round trip mismatch")
+	}
+}
@@ -0,0 +1,65 @@
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// String2File replaces fileName with data atomically: data goes to a temp
+// file which is synced and renamed over fileName, the previous content is
+// kept in fileName.bak.
+func String2File(data []byte, fileName string) error {
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return err
+	}
+
+	tmpFile := fileName + ".tmp"
+	if err := string2FileNotSafe(data, tmpFile); err != nil {
+		return err
+	}
+
+	prev, err := ioutil.ReadFile(fileName)
+	if err == nil {
+		if err := string2FileNotSafe(prev, fileName+".bak"); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.Rename(tmpFile, fileName)
+}
+
+func string2FileNotSafe(data []byte, fileName string) error {
+	f, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// File2String reads fileName, falling back to the backup String2File leaves
+// behind. It returns nil without error when neither exists.
+func File2String(fileName string) ([]byte, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err == nil {
+		return data, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	data, err = ioutil.ReadFile(fileName + ".bak")
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
@@ -0,0 +1,20 @@
+package perm
+
+const (
+	PermPriority = 0x1 << 3
+	PermRead     = 0x1 << 2
+	PermWrite    = 0x1 << 1
+	PermInherit  = 0x1 << 0
+)
+
+func IsReadable(perm int) bool {
+	return (perm & PermRead) == PermRead
+}
+
+func IsWriteable(perm int) bool {
+	return (perm & PermWrite) == PermWrite
+}
+
+func IsInherited(perm int) bool {
+	return (perm & PermInherit) == PermInherit
+}
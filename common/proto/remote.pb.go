@@ -51,6 +51,11 @@ const (
 	RequestCode_GET_HAS_UNIT_SUB_UNUNIT_TOPIC_LIST RequestCode = 16
 	RequestCode_UPDATE_NAMESRV_CONFIG              RequestCode = 17
 	RequestCode_GET_NAMESRV_CONFIG                 RequestCode = 18
+	RequestCode_ADD_WRITE_PERM_OF_BROKER           RequestCode = 19
+	RequestCode_SUBSCRIBE_TOPIC_ROUTE              RequestCode = 20
+	RequestCode_UNSUBSCRIBE_TOPIC_ROUTE            RequestCode = 21
+	RequestCode_NOTIFY_TOPIC_ROUTE_CHANGED         RequestCode = 22
+	RequestCode_NOTIFY_SHUTDOWN                    RequestCode = 23 // pushed oneway when the server is going away
 )
 
 // Enum value maps for RequestCode.
@@ -75,6 +80,11 @@ var (
 		16: "GET_HAS_UNIT_SUB_UNUNIT_TOPIC_LIST",
 		17: "UPDATE_NAMESRV_CONFIG",
 		18: "GET_NAMESRV_CONFIG",
+		19: "ADD_WRITE_PERM_OF_BROKER",
+		20: "SUBSCRIBE_TOPIC_ROUTE",
+		21: "UNSUBSCRIBE_TOPIC_ROUTE",
+		22: "NOTIFY_TOPIC_ROUTE_CHANGED",
+		23: "NOTIFY_SHUTDOWN",
 	}
 	RequestCode_value = map[string]int32{
 		"PUT_KV_CONFIG":                      0,
@@ -96,6 +106,11 @@ var (
 		"GET_HAS_UNIT_SUB_UNUNIT_TOPIC_LIST": 16,
 		"UPDATE_NAMESRV_CONFIG":              17,
 		"GET_NAMESRV_CONFIG":                 18,
+		"ADD_WRITE_PERM_OF_BROKER":           19,
+		"SUBSCRIBE_TOPIC_ROUTE":              20,
+		"UNSUBSCRIBE_TOPIC_ROUTE":            21,
+		"NOTIFY_TOPIC_ROUTE_CHANGED":         22,
+		"NOTIFY_SHUTDOWN":                    23,
 	}
 )
 
@@ -136,6 +151,9 @@ const (
 	ResponseCode_TRANSACTION_FAILED         ResponseCode = 4
 	ResponseCode_QUERY_NOT_FOUND            ResponseCode = 5
 	ResponseCode_TOPIC_NOT_EXIST            ResponseCode = 6
+	ResponseCode_NO_PERMISSION              ResponseCode = 7
+	ResponseCode_HEADER_DECODE_ERROR        ResponseCode = 8 // remark carries the parse error
+	ResponseCode_ACCESS_DENIED              ResponseCode = 9
 )
 
 // Enum value maps for ResponseCode.
@@ -148,6 +166,9 @@ var (
 		4: "TRANSACTION_FAILED",
 		5: "QUERY_NOT_FOUND",
 		6: "TOPIC_NOT_EXIST",
+		7: "NO_PERMISSION",
+		8: "HEADER_DECODE_ERROR",
+		9: "ACCESS_DENIED",
 	}
 	ResponseCode_value = map[string]int32{
 		"SUCCESS":                    0,
@@ -157,6 +178,9 @@ var (
 		"TRANSACTION_FAILED":         4,
 		"QUERY_NOT_FOUND":            5,
 		"TOPIC_NOT_EXIST":            6,
+		"NO_PERMISSION":              7,
+		"HEADER_DECODE_ERROR":        8,
+		"ACCESS_DENIED":              9,
 	}
 )
 
@@ -192,11 +216,14 @@ type RemoteCommand struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Version int32  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
-	Code    int32  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
-	Header  []byte `protobuf:"bytes,3,opt,name=header,proto3" json:"header,omitempty"`
-	Body    []byte `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
-	Remark  string `protobuf:"bytes,5,opt,name=remark,proto3" json:"remark,omitempty"`
+	Version   int32             `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Code      int32             `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Header    []byte            `protobuf:"bytes,3,opt,name=header,proto3" json:"header,omitempty"`
+	Body      []byte            `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	Remark    string            `protobuf:"bytes,5,opt,name=remark,proto3" json:"remark,omitempty"`
+	Opaque    int32             `protobuf:"varint,6,opt,name=opaque,proto3" json:"opaque,omitempty"`                                                                                              // request id, copied to the response
+	Flag      int32             `protobuf:"varint,7,opt,name=flag,proto3" json:"flag,omitempty"`                                                                                                  // bit 0: response, bit 1: oneway
+	ExtFields map[string]string `protobuf:"bytes,8,rep,name=extFields,proto3" json:"extFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // AccessKey and Signature of signed requests
 }
 
 func (x *RemoteCommand) Reset() {
@@ -266,6 +293,224 @@ func (x *RemoteCommand) GetRemark() string {
 	return ""
 }
 
+func (x *RemoteCommand) GetOpaque() int32 {
+	if x != nil {
+		return x.Opaque
+	}
+	return 0
+}
+
+func (x *RemoteCommand) GetFlag() int32 {
+	if x != nil {
+		return x.Flag
+	}
+	return 0
+}
+
+func (x *RemoteCommand) GetExtFields() map[string]string {
+	if x != nil {
+		return x.ExtFields
+	}
+	return nil
+}
+
+type DataVersion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Counter   int64 `protobuf:"varint,2,opt,name=counter,proto3" json:"counter,omitempty"`
+}
+
+func (x *DataVersion) Reset() {
+	*x = DataVersion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DataVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataVersion) ProtoMessage() {}
+
+func (x *DataVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataVersion.ProtoReflect.Descriptor instead.
+func (*DataVersion) Descriptor() ([]byte, []int) {
+	return file_remote_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DataVersion) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *DataVersion) GetCounter() int64 {
+	if x != nil {
+		return x.Counter
+	}
+	return 0
+}
+
+type TopicConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TopicName      string `protobuf:"bytes,1,opt,name=topicName,proto3" json:"topicName,omitempty"`
+	ReadQueueNums  int32  `protobuf:"varint,2,opt,name=readQueueNums,proto3" json:"readQueueNums,omitempty"`
+	WriteQueueNums int32  `protobuf:"varint,3,opt,name=writeQueueNums,proto3" json:"writeQueueNums,omitempty"`
+	Perm           int32  `protobuf:"varint,4,opt,name=perm,proto3" json:"perm,omitempty"`
+	TopicSysFlag   int32  `protobuf:"varint,5,opt,name=topicSysFlag,proto3" json:"topicSysFlag,omitempty"`
+	Order          bool   `protobuf:"varint,6,opt,name=order,proto3" json:"order,omitempty"`
+}
+
+func (x *TopicConfig) Reset() {
+	*x = TopicConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TopicConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopicConfig) ProtoMessage() {}
+
+func (x *TopicConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopicConfig.ProtoReflect.Descriptor instead.
+func (*TopicConfig) Descriptor() ([]byte, []int) {
+	return file_remote_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TopicConfig) GetTopicName() string {
+	if x != nil {
+		return x.TopicName
+	}
+	return ""
+}
+
+func (x *TopicConfig) GetReadQueueNums() int32 {
+	if x != nil {
+		return x.ReadQueueNums
+	}
+	return 0
+}
+
+func (x *TopicConfig) GetWriteQueueNums() int32 {
+	if x != nil {
+		return x.WriteQueueNums
+	}
+	return 0
+}
+
+func (x *TopicConfig) GetPerm() int32 {
+	if x != nil {
+		return x.Perm
+	}
+	return 0
+}
+
+func (x *TopicConfig) GetTopicSysFlag() int32 {
+	if x != nil {
+		return x.TopicSysFlag
+	}
+	return 0
+}
+
+func (x *TopicConfig) GetOrder() bool {
+	if x != nil {
+		return x.Order
+	}
+	return false
+}
+
+type TopicConfigSerializeWrapper struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TopicConfigTable map[string]*TopicConfig `protobuf:"bytes,1,rep,name=topicConfigTable,proto3" json:"topicConfigTable,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	DataVersion      *DataVersion            `protobuf:"bytes,2,opt,name=dataVersion,proto3" json:"dataVersion,omitempty"`
+}
+
+func (x *TopicConfigSerializeWrapper) Reset() {
+	*x = TopicConfigSerializeWrapper{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TopicConfigSerializeWrapper) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopicConfigSerializeWrapper) ProtoMessage() {}
+
+func (x *TopicConfigSerializeWrapper) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopicConfigSerializeWrapper.ProtoReflect.Descriptor instead.
+func (*TopicConfigSerializeWrapper) Descriptor() ([]byte, []int) {
+	return file_remote_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TopicConfigSerializeWrapper) GetTopicConfigTable() map[string]*TopicConfig {
+	if x != nil {
+		return x.TopicConfigTable
+	}
+	return nil
+}
+
+func (x *TopicConfigSerializeWrapper) GetDataVersion() *DataVersion {
+	if x != nil {
+		return x.DataVersion
+	}
+	return nil
+}
+
 // PUT_KV_CONFIG
 type PutKVConfigRequestHeader struct {
 	state         protoimpl.MessageState
@@ -280,7 +525,7 @@ type PutKVConfigRequestHeader struct {
 func (x *PutKVConfigRequestHeader) Reset() {
 	*x = PutKVConfigRequestHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[1]
+		mi := &file_remote_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -293,7 +538,7 @@ func (x *PutKVConfigRequestHeader) String() string {
 func (*PutKVConfigRequestHeader) ProtoMessage() {}
 
 func (x *PutKVConfigRequestHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[1]
+	mi := &file_remote_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -306,7 +551,7 @@ func (x *PutKVConfigRequestHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PutKVConfigRequestHeader.ProtoReflect.Descriptor instead.
 func (*PutKVConfigRequestHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{1}
+	return file_remote_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *PutKVConfigRequestHeader) GetNamespace() string {
@@ -343,7 +588,7 @@ type GetKVConfigRequestHeader struct {
 func (x *GetKVConfigRequestHeader) Reset() {
 	*x = GetKVConfigRequestHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[2]
+		mi := &file_remote_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -356,7 +601,7 @@ func (x *GetKVConfigRequestHeader) String() string {
 func (*GetKVConfigRequestHeader) ProtoMessage() {}
 
 func (x *GetKVConfigRequestHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[2]
+	mi := &file_remote_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -369,7 +614,7 @@ func (x *GetKVConfigRequestHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetKVConfigRequestHeader.ProtoReflect.Descriptor instead.
 func (*GetKVConfigRequestHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{2}
+	return file_remote_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetKVConfigRequestHeader) GetNamespace() string {
@@ -397,7 +642,7 @@ type GetKVConfigResponseHeader struct {
 func (x *GetKVConfigResponseHeader) Reset() {
 	*x = GetKVConfigResponseHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[3]
+		mi := &file_remote_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -410,7 +655,7 @@ func (x *GetKVConfigResponseHeader) String() string {
 func (*GetKVConfigResponseHeader) ProtoMessage() {}
 
 func (x *GetKVConfigResponseHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[3]
+	mi := &file_remote_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -423,7 +668,7 @@ func (x *GetKVConfigResponseHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetKVConfigResponseHeader.ProtoReflect.Descriptor instead.
 func (*GetKVConfigResponseHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{3}
+	return file_remote_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetKVConfigResponseHeader) GetValue() string {
@@ -446,7 +691,7 @@ type DeleteKVConfigRequestHeader struct {
 func (x *DeleteKVConfigRequestHeader) Reset() {
 	*x = DeleteKVConfigRequestHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[4]
+		mi := &file_remote_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -459,7 +704,7 @@ func (x *DeleteKVConfigRequestHeader) String() string {
 func (*DeleteKVConfigRequestHeader) ProtoMessage() {}
 
 func (x *DeleteKVConfigRequestHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[4]
+	mi := &file_remote_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -472,7 +717,7 @@ func (x *DeleteKVConfigRequestHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteKVConfigRequestHeader.ProtoReflect.Descriptor instead.
 func (*DeleteKVConfigRequestHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{4}
+	return file_remote_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DeleteKVConfigRequestHeader) GetNamespace() string {
@@ -504,7 +749,7 @@ type QueryDataVersionRequestHeader struct {
 func (x *QueryDataVersionRequestHeader) Reset() {
 	*x = QueryDataVersionRequestHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[5]
+		mi := &file_remote_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -517,7 +762,7 @@ func (x *QueryDataVersionRequestHeader) String() string {
 func (*QueryDataVersionRequestHeader) ProtoMessage() {}
 
 func (x *QueryDataVersionRequestHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[5]
+	mi := &file_remote_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -530,7 +775,7 @@ func (x *QueryDataVersionRequestHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QueryDataVersionRequestHeader.ProtoReflect.Descriptor instead.
 func (*QueryDataVersionRequestHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{5}
+	return file_remote_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *QueryDataVersionRequestHeader) GetBrokerName() string {
@@ -572,7 +817,7 @@ type QueryDataVersionResponseHeader struct {
 func (x *QueryDataVersionResponseHeader) Reset() {
 	*x = QueryDataVersionResponseHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[6]
+		mi := &file_remote_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -585,7 +830,7 @@ func (x *QueryDataVersionResponseHeader) String() string {
 func (*QueryDataVersionResponseHeader) ProtoMessage() {}
 
 func (x *QueryDataVersionResponseHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[6]
+	mi := &file_remote_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -598,7 +843,7 @@ func (x *QueryDataVersionResponseHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QueryDataVersionResponseHeader.ProtoReflect.Descriptor instead.
 func (*QueryDataVersionResponseHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{6}
+	return file_remote_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *QueryDataVersionResponseHeader) GetChanged() bool {
@@ -626,7 +871,7 @@ type RegisterBrokerRequestHeader struct {
 func (x *RegisterBrokerRequestHeader) Reset() {
 	*x = RegisterBrokerRequestHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[7]
+		mi := &file_remote_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -639,7 +884,7 @@ func (x *RegisterBrokerRequestHeader) String() string {
 func (*RegisterBrokerRequestHeader) ProtoMessage() {}
 
 func (x *RegisterBrokerRequestHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[7]
+	mi := &file_remote_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -652,7 +897,7 @@ func (x *RegisterBrokerRequestHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterBrokerRequestHeader.ProtoReflect.Descriptor instead.
 func (*RegisterBrokerRequestHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{7}
+	return file_remote_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *RegisterBrokerRequestHeader) GetBrokerName() string {
@@ -716,7 +961,7 @@ type RegisterBrokerResponseHeader struct {
 func (x *RegisterBrokerResponseHeader) Reset() {
 	*x = RegisterBrokerResponseHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[8]
+		mi := &file_remote_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -729,7 +974,7 @@ func (x *RegisterBrokerResponseHeader) String() string {
 func (*RegisterBrokerResponseHeader) ProtoMessage() {}
 
 func (x *RegisterBrokerResponseHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[8]
+	mi := &file_remote_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -742,7 +987,7 @@ func (x *RegisterBrokerResponseHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterBrokerResponseHeader.ProtoReflect.Descriptor instead.
 func (*RegisterBrokerResponseHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{8}
+	return file_remote_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *RegisterBrokerResponseHeader) GetHaServerAddr() string {
@@ -764,13 +1009,14 @@ type RegisterBrokerBody struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	FilterServerList []string `protobuf:"bytes,1,rep,name=filterServerList,proto3" json:"filterServerList,omitempty"`
+	FilterServerList            []string                     `protobuf:"bytes,1,rep,name=filterServerList,proto3" json:"filterServerList,omitempty"`
+	TopicConfigSerializeWrapper *TopicConfigSerializeWrapper `protobuf:"bytes,2,opt,name=topicConfigSerializeWrapper,proto3" json:"topicConfigSerializeWrapper,omitempty"`
 }
 
 func (x *RegisterBrokerBody) Reset() {
 	*x = RegisterBrokerBody{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[9]
+		mi := &file_remote_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -783,7 +1029,7 @@ func (x *RegisterBrokerBody) String() string {
 func (*RegisterBrokerBody) ProtoMessage() {}
 
 func (x *RegisterBrokerBody) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[9]
+	mi := &file_remote_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -796,7 +1042,7 @@ func (x *RegisterBrokerBody) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterBrokerBody.ProtoReflect.Descriptor instead.
 func (*RegisterBrokerBody) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{9}
+	return file_remote_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *RegisterBrokerBody) GetFilterServerList() []string {
@@ -806,6 +1052,13 @@ func (x *RegisterBrokerBody) GetFilterServerList() []string {
 	return nil
 }
 
+func (x *RegisterBrokerBody) GetTopicConfigSerializeWrapper() *TopicConfigSerializeWrapper {
+	if x != nil {
+		return x.TopicConfigSerializeWrapper
+	}
+	return nil
+}
+
 // UNREGISTER_BROKER
 type UnRegisterBrokerHeader struct {
 	state         protoimpl.MessageState
@@ -821,7 +1074,7 @@ type UnRegisterBrokerHeader struct {
 func (x *UnRegisterBrokerHeader) Reset() {
 	*x = UnRegisterBrokerHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[10]
+		mi := &file_remote_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -834,7 +1087,7 @@ func (x *UnRegisterBrokerHeader) String() string {
 func (*UnRegisterBrokerHeader) ProtoMessage() {}
 
 func (x *UnRegisterBrokerHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[10]
+	mi := &file_remote_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -847,7 +1100,7 @@ func (x *UnRegisterBrokerHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnRegisterBrokerHeader.ProtoReflect.Descriptor instead.
 func (*UnRegisterBrokerHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{10}
+	return file_remote_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *UnRegisterBrokerHeader) GetBrokerName() string {
@@ -890,7 +1143,7 @@ type GetRouteInfoRequestHeader struct {
 func (x *GetRouteInfoRequestHeader) Reset() {
 	*x = GetRouteInfoRequestHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[11]
+		mi := &file_remote_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -903,7 +1156,7 @@ func (x *GetRouteInfoRequestHeader) String() string {
 func (*GetRouteInfoRequestHeader) ProtoMessage() {}
 
 func (x *GetRouteInfoRequestHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[11]
+	mi := &file_remote_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -916,7 +1169,7 @@ func (x *GetRouteInfoRequestHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRouteInfoRequestHeader.ProtoReflect.Descriptor instead.
 func (*GetRouteInfoRequestHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{11}
+	return file_remote_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *GetRouteInfoRequestHeader) GetTopic() string {
@@ -938,7 +1191,7 @@ type WipeWritePermOfBrokerRequestHeader struct {
 func (x *WipeWritePermOfBrokerRequestHeader) Reset() {
 	*x = WipeWritePermOfBrokerRequestHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[12]
+		mi := &file_remote_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -951,7 +1204,7 @@ func (x *WipeWritePermOfBrokerRequestHeader) String() string {
 func (*WipeWritePermOfBrokerRequestHeader) ProtoMessage() {}
 
 func (x *WipeWritePermOfBrokerRequestHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[12]
+	mi := &file_remote_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -964,7 +1217,7 @@ func (x *WipeWritePermOfBrokerRequestHeader) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use WipeWritePermOfBrokerRequestHeader.ProtoReflect.Descriptor instead.
 func (*WipeWritePermOfBrokerRequestHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{12}
+	return file_remote_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *WipeWritePermOfBrokerRequestHeader) GetBrokerName() string {
@@ -985,7 +1238,7 @@ type WipeWritePermOfBrokerResponseHeader struct {
 func (x *WipeWritePermOfBrokerResponseHeader) Reset() {
 	*x = WipeWritePermOfBrokerResponseHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[13]
+		mi := &file_remote_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -998,7 +1251,7 @@ func (x *WipeWritePermOfBrokerResponseHeader) String() string {
 func (*WipeWritePermOfBrokerResponseHeader) ProtoMessage() {}
 
 func (x *WipeWritePermOfBrokerResponseHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[13]
+	mi := &file_remote_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1011,7 +1264,7 @@ func (x *WipeWritePermOfBrokerResponseHeader) ProtoReflect() protoreflect.Messag
 
 // Deprecated: Use WipeWritePermOfBrokerResponseHeader.ProtoReflect.Descriptor instead.
 func (*WipeWritePermOfBrokerResponseHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{13}
+	return file_remote_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *WipeWritePermOfBrokerResponseHeader) GetWipeTopicCount() int32 {
@@ -1021,32 +1274,32 @@ func (x *WipeWritePermOfBrokerResponseHeader) GetWipeTopicCount() int32 {
 	return 0
 }
 
-// DELETE_TOPIC_IN_NAMESRV
-type DeleteTopicInNamesrvRequestHeader struct {
+// ADD_WRITE_PERM_OF_BROKER
+type AddWritePermOfBrokerRequestHeader struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	BrokerName string `protobuf:"bytes,1,opt,name=brokerName,proto3" json:"brokerName,omitempty"`
 }
 
-func (x *DeleteTopicInNamesrvRequestHeader) Reset() {
-	*x = DeleteTopicInNamesrvRequestHeader{}
+func (x *AddWritePermOfBrokerRequestHeader) Reset() {
+	*x = AddWritePermOfBrokerRequestHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[14]
+		mi := &file_remote_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DeleteTopicInNamesrvRequestHeader) String() string {
+func (x *AddWritePermOfBrokerRequestHeader) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteTopicInNamesrvRequestHeader) ProtoMessage() {}
+func (*AddWritePermOfBrokerRequestHeader) ProtoMessage() {}
 
-func (x *DeleteTopicInNamesrvRequestHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[14]
+func (x *AddWritePermOfBrokerRequestHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1057,12 +1310,107 @@ func (x *DeleteTopicInNamesrvRequestHeader) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteTopicInNamesrvRequestHeader.ProtoReflect.Descriptor instead.
-func (*DeleteTopicInNamesrvRequestHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use AddWritePermOfBrokerRequestHeader.ProtoReflect.Descriptor instead.
+func (*AddWritePermOfBrokerRequestHeader) Descriptor() ([]byte, []int) {
+	return file_remote_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *DeleteTopicInNamesrvRequestHeader) GetTopic() string {
+func (x *AddWritePermOfBrokerRequestHeader) GetBrokerName() string {
+	if x != nil {
+		return x.BrokerName
+	}
+	return ""
+}
+
+type AddWritePermOfBrokerResponseHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AddTopicCount int32 `protobuf:"varint,1,opt,name=addTopicCount,proto3" json:"addTopicCount,omitempty"`
+}
+
+func (x *AddWritePermOfBrokerResponseHeader) Reset() {
+	*x = AddWritePermOfBrokerResponseHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddWritePermOfBrokerResponseHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddWritePermOfBrokerResponseHeader) ProtoMessage() {}
+
+func (x *AddWritePermOfBrokerResponseHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddWritePermOfBrokerResponseHeader.ProtoReflect.Descriptor instead.
+func (*AddWritePermOfBrokerResponseHeader) Descriptor() ([]byte, []int) {
+	return file_remote_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AddWritePermOfBrokerResponseHeader) GetAddTopicCount() int32 {
+	if x != nil {
+		return x.AddTopicCount
+	}
+	return 0
+}
+
+// DELETE_TOPIC_IN_NAMESRV
+type DeleteTopicInNamesrvRequestHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+}
+
+func (x *DeleteTopicInNamesrvRequestHeader) Reset() {
+	*x = DeleteTopicInNamesrvRequestHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteTopicInNamesrvRequestHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTopicInNamesrvRequestHeader) ProtoMessage() {}
+
+func (x *DeleteTopicInNamesrvRequestHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTopicInNamesrvRequestHeader.ProtoReflect.Descriptor instead.
+func (*DeleteTopicInNamesrvRequestHeader) Descriptor() ([]byte, []int) {
+	return file_remote_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteTopicInNamesrvRequestHeader) GetTopic() string {
 	if x != nil {
 		return x.Topic
 	}
@@ -1081,7 +1429,7 @@ type GetKVListByNamespaceRequestHeader struct {
 func (x *GetKVListByNamespaceRequestHeader) Reset() {
 	*x = GetKVListByNamespaceRequestHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[15]
+		mi := &file_remote_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1094,7 +1442,7 @@ func (x *GetKVListByNamespaceRequestHeader) String() string {
 func (*GetKVListByNamespaceRequestHeader) ProtoMessage() {}
 
 func (x *GetKVListByNamespaceRequestHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[15]
+	mi := &file_remote_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1107,7 +1455,7 @@ func (x *GetKVListByNamespaceRequestHeader) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use GetKVListByNamespaceRequestHeader.ProtoReflect.Descriptor instead.
 func (*GetKVListByNamespaceRequestHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{15}
+	return file_remote_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *GetKVListByNamespaceRequestHeader) GetNamespace() string {
@@ -1129,7 +1477,7 @@ type GetTopicsByClusterRequestHeader struct {
 func (x *GetTopicsByClusterRequestHeader) Reset() {
 	*x = GetTopicsByClusterRequestHeader{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_msgTypes[16]
+		mi := &file_remote_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1142,7 +1490,7 @@ func (x *GetTopicsByClusterRequestHeader) String() string {
 func (*GetTopicsByClusterRequestHeader) ProtoMessage() {}
 
 func (x *GetTopicsByClusterRequestHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_msgTypes[16]
+	mi := &file_remote_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1155,7 +1503,7 @@ func (x *GetTopicsByClusterRequestHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTopicsByClusterRequestHeader.ProtoReflect.Descriptor instead.
 func (*GetTopicsByClusterRequestHeader) Descriptor() ([]byte, []int) {
-	return file_remote_proto_rawDescGZIP(), []int{16}
+	return file_remote_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *GetTopicsByClusterRequestHeader) GetCluster() string {
@@ -1165,11 +1513,156 @@ func (x *GetTopicsByClusterRequestHeader) GetCluster() string {
 	return ""
 }
 
+// SUBSCRIBE_TOPIC_ROUTE
+type SubscribeTopicRouteRequestHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Topics []string `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
+}
+
+func (x *SubscribeTopicRouteRequestHeader) Reset() {
+	*x = SubscribeTopicRouteRequestHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeTopicRouteRequestHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeTopicRouteRequestHeader) ProtoMessage() {}
+
+func (x *SubscribeTopicRouteRequestHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeTopicRouteRequestHeader.ProtoReflect.Descriptor instead.
+func (*SubscribeTopicRouteRequestHeader) Descriptor() ([]byte, []int) {
+	return file_remote_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SubscribeTopicRouteRequestHeader) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+// UNSUBSCRIBE_TOPIC_ROUTE
+type UnSubscribeTopicRouteRequestHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Topics []string `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
+}
+
+func (x *UnSubscribeTopicRouteRequestHeader) Reset() {
+	*x = UnSubscribeTopicRouteRequestHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnSubscribeTopicRouteRequestHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnSubscribeTopicRouteRequestHeader) ProtoMessage() {}
+
+func (x *UnSubscribeTopicRouteRequestHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnSubscribeTopicRouteRequestHeader.ProtoReflect.Descriptor instead.
+func (*UnSubscribeTopicRouteRequestHeader) Descriptor() ([]byte, []int) {
+	return file_remote_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *UnSubscribeTopicRouteRequestHeader) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+// NOTIFY_TOPIC_ROUTE_CHANGED
+// pushed by name server, body is the json route data, empty if the topic has no route any more
+type NotifyTopicRouteChangedRequestHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+}
+
+func (x *NotifyTopicRouteChangedRequestHeader) Reset() {
+	*x = NotifyTopicRouteChangedRequestHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NotifyTopicRouteChangedRequestHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotifyTopicRouteChangedRequestHeader) ProtoMessage() {}
+
+func (x *NotifyTopicRouteChangedRequestHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotifyTopicRouteChangedRequestHeader.ProtoReflect.Descriptor instead.
+func (*NotifyTopicRouteChangedRequestHeader) Descriptor() ([]byte, []int) {
+	return file_remote_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *NotifyTopicRouteChangedRequestHeader) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
 var File_remote_proto protoreflect.FileDescriptor
 
 var file_remote_proto_rawDesc = []byte{
 	0x0a, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
-	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x22, 0x81, 0x01, 0x0a, 0x0d, 0x52, 0x65, 0x6d, 0x6f, 0x74,
+	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x22, 0xaf, 0x02, 0x0a, 0x0d, 0x52, 0x65, 0x6d, 0x6f, 0x74,
 	0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
 	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
 	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
@@ -1177,88 +1670,149 @@ var file_remote_proto_rawDesc = []byte{
 	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x12,
 	0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6f,
 	0x64, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x22, 0x60, 0x0a, 0x18, 0x50, 0x75,
-	0x74, 0x4b, 0x56, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70,
-	0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73,
-	0x70, 0x61, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x4a, 0x0a, 0x18,
-	0x47, 0x65, 0x74, 0x4b, 0x56, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65,
-	0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d,
-	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x31, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x4b,
-	0x56, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48,
-	0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x4d, 0x0a, 0x1b, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x4b, 0x56, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61,
-	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e,
-	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x9d, 0x01, 0x0a, 0x1d, 0x51,
-	0x75, 0x65, 0x72, 0x79, 0x44, 0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a,
-	0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
-	0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x20, 0x0a, 0x0b,
-	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a,
-	0x0a, 0x08, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x08, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x49, 0x64, 0x22, 0x3a, 0x0a, 0x1e, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x44, 0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07,
-	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x63,
-	0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x22, 0xfd, 0x01, 0x0a, 0x1b, 0x52, 0x65, 0x67, 0x69, 0x73,
-	0x74, 0x65, 0x72, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x70,
+	0x61, 0x71, 0x75, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x70, 0x61, 0x71,
+	0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x6c, 0x61, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x04, 0x66, 0x6c, 0x61, 0x67, 0x12, 0x42, 0x0a, 0x09, 0x65, 0x78, 0x74, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x63, 0x6f, 0x6d, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x2e, 0x45, 0x78, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x09, 0x65, 0x78, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x1a, 0x3c, 0x0a, 0x0e, 0x45, 0x78,
+	0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x45, 0x0a, 0x0b, 0x44, 0x61, 0x74, 0x61,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x22,
+	0xc7, 0x01, 0x0a, 0x0b, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x24, 0x0a,
+	0x0d, 0x72, 0x65, 0x61, 0x64, 0x51, 0x75, 0x65, 0x75, 0x65, 0x4e, 0x75, 0x6d, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x72, 0x65, 0x61, 0x64, 0x51, 0x75, 0x65, 0x75, 0x65, 0x4e,
+	0x75, 0x6d, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x77, 0x72, 0x69, 0x74, 0x65, 0x51, 0x75, 0x65, 0x75,
+	0x65, 0x4e, 0x75, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x77, 0x72, 0x69,
+	0x74, 0x65, 0x51, 0x75, 0x65, 0x75, 0x65, 0x4e, 0x75, 0x6d, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x65, 0x72, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x65, 0x72, 0x6d, 0x12,
+	0x22, 0x0a, 0x0c, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x53, 0x79, 0x73, 0x46, 0x6c, 0x61, 0x67, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x53, 0x79, 0x73, 0x46,
+	0x6c, 0x61, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x22, 0x95, 0x02, 0x0a, 0x1b, 0x54, 0x6f,
+	0x70, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x69,
+	0x7a, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x12, 0x65, 0x0a, 0x10, 0x74, 0x6f, 0x70,
+	0x69, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x39, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x54, 0x6f, 0x70,
+	0x69, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x2e, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x10,
+	0x74, 0x6f, 0x70, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x54, 0x61, 0x62, 0x6c, 0x65,
+	0x12, 0x35, 0x0a, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x44,
+	0x61, 0x74, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x64, 0x61, 0x74, 0x61,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x1a, 0x58, 0x0a, 0x15, 0x54, 0x6f, 0x70, 0x69, 0x63,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x29, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x54, 0x6f, 0x70, 0x69, 0x63,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x60, 0x0a, 0x18, 0x50, 0x75, 0x74, 0x4b, 0x56, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1c, 0x0a,
+	0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x22, 0x4a, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x4b, 0x56, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12,
+	0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22,
+	0x31, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x4b, 0x56, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x22, 0x4d, 0x0a, 0x1b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4b, 0x56, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x22, 0x9d, 0x01, 0x0a, 0x1d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x44, 0x61, 0x74, 0x61, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x41, 0x64, 0x64,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x41,
+	0x64, 0x64, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65,
+	0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x49,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x49,
+	0x64, 0x22, 0x3a, 0x0a, 0x1e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x44, 0x61, 0x74, 0x61, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x22, 0xfd, 0x01,
+	0x0a, 0x1b, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a,
+	0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a,
+	0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x20, 0x0a,
+	0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x22, 0x0a, 0x0c, 0x68, 0x61, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x68, 0x61, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x41,
+	0x64, 0x64, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x49, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x49, 0x64, 0x12,
+	0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x12,
+	0x1c, 0x0a, 0x09, 0x62, 0x6f, 0x64, 0x79, 0x43, 0x72, 0x63, 0x33, 0x32, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x62, 0x6f, 0x64, 0x79, 0x43, 0x72, 0x63, 0x33, 0x32, 0x22, 0x62, 0x0a,
+	0x1c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x22, 0x0a,
+	0x0c, 0x68, 0x61, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x68, 0x61, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x41, 0x64, 0x64,
+	0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x41, 0x64, 0x64,
+	0x72, 0x22, 0xa7, 0x01, 0x0a, 0x12, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x72,
+	0x6f, 0x6b, 0x65, 0x72, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x2a, 0x0a, 0x10, 0x66, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x10, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x4c, 0x69, 0x73, 0x74, 0x12, 0x65, 0x0a, 0x1b, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x57, 0x72, 0x61, 0x70,
+	0x70, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x6f, 0x6d, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x65,
+	0x72, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x52, 0x1b,
+	0x74, 0x6f, 0x70, 0x69, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x53, 0x65, 0x72, 0x69, 0x61,
+	0x6c, 0x69, 0x7a, 0x65, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x22, 0x96, 0x01, 0x0a, 0x16,
+	0x55, 0x6e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72,
 	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72,
 	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x72, 0x6f, 0x6b,
 	0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72,
 	0x41, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x72, 0x6f, 0x6b,
 	0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65,
 	0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6c, 0x75,
-	0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x68, 0x61, 0x53, 0x65,
-	0x72, 0x76, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
-	0x68, 0x61, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1a, 0x0a, 0x08,
-	0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
-	0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70,
-	0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f,
-	0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x6f, 0x64, 0x79,
-	0x43, 0x72, 0x63, 0x33, 0x32, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x62, 0x6f, 0x64,
-	0x79, 0x43, 0x72, 0x63, 0x33, 0x32, 0x22, 0x62, 0x0a, 0x1c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
-	0x65, 0x72, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x68, 0x61, 0x53, 0x65, 0x72, 0x76,
-	0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x68, 0x61,
-	0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x61,
-	0x73, 0x74, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x6d, 0x61, 0x73, 0x74, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x22, 0x40, 0x0a, 0x12, 0x52, 0x65,
-	0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x42, 0x6f, 0x64, 0x79,
-	0x12, 0x2a, 0x0a, 0x10, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
-	0x4c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x66, 0x69, 0x6c, 0x74,
-	0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x96, 0x01, 0x0a,
-	0x16, 0x55, 0x6e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x72, 0x6f, 0x6b, 0x65,
-	0x72, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65,
-	0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x72, 0x6f,
-	0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65,
-	0x72, 0x41, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x72, 0x6f,
-	0x6b, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6c, 0x75, 0x73, 0x74,
-	0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6c,
-	0x75, 0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x72, 0x6f,
-	0x6b, 0x65, 0x72, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x62, 0x72, 0x6f,
-	0x6b, 0x65, 0x72, 0x49, 0x64, 0x22, 0x31, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x75, 0x74,
-	0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64,
-	0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x22, 0x44, 0x0a, 0x22, 0x57, 0x69, 0x70, 0x65,
-	0x57, 0x72, 0x69, 0x74, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x4f, 0x66, 0x42, 0x72, 0x6f, 0x6b, 0x65,
-	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1e,
-	0x0a, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x4d,
-	0x0a, 0x23, 0x57, 0x69, 0x70, 0x65, 0x57, 0x72, 0x69, 0x74, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x4f,
-	0x66, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48,
-	0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x77, 0x69, 0x70, 0x65, 0x54, 0x6f, 0x70,
-	0x69, 0x63, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x77,
-	0x69, 0x70, 0x65, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x39, 0x0a,
+	0x73, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x72, 0x6f, 0x6b,
+	0x65, 0x72, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x62, 0x72, 0x6f, 0x6b,
+	0x65, 0x72, 0x49, 0x64, 0x22, 0x31, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x75, 0x74, 0x65,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x22, 0x44, 0x0a, 0x22, 0x57, 0x69, 0x70, 0x65, 0x57,
+	0x72, 0x69, 0x74, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x4f, 0x66, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a,
+	0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x4d, 0x0a,
+	0x23, 0x57, 0x69, 0x70, 0x65, 0x57, 0x72, 0x69, 0x74, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x4f, 0x66,
+	0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x77, 0x69, 0x70, 0x65, 0x54, 0x6f, 0x70, 0x69,
+	0x63, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x77, 0x69,
+	0x70, 0x65, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x43, 0x0a, 0x21,
+	0x41, 0x64, 0x64, 0x57, 0x72, 0x69, 0x74, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x4f, 0x66, 0x42, 0x72,
+	0x6f, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d,
+	0x65, 0x22, 0x4a, 0x0a, 0x22, 0x41, 0x64, 0x64, 0x57, 0x72, 0x69, 0x74, 0x65, 0x50, 0x65, 0x72,
+	0x6d, 0x4f, 0x66, 0x42, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x61, 0x64, 0x64, 0x54, 0x6f,
+	0x70, 0x69, 0x63, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d,
+	0x61, 0x64, 0x64, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x39, 0x0a,
 	0x21, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x49, 0x6e, 0x4e, 0x61,
 	0x6d, 0x65, 0x73, 0x72, 0x76, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64,
 	0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28,
@@ -1270,57 +1824,81 @@ var file_remote_proto_rawDesc = []byte{
 	0x65, 0x74, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x73, 0x42, 0x79, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65,
 	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x18,
 	0x0a, 0x07, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x2a, 0x9a, 0x04, 0x0a, 0x0b, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x50, 0x55, 0x54, 0x5f,
-	0x4b, 0x56, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x47,
-	0x45, 0x54, 0x5f, 0x4b, 0x56, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10, 0x01, 0x12, 0x14,
-	0x0a, 0x10, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x5f, 0x4b, 0x56, 0x5f, 0x43, 0x4f, 0x4e, 0x46,
-	0x49, 0x47, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12, 0x51, 0x55, 0x45, 0x52, 0x59, 0x5f, 0x44, 0x41,
-	0x54, 0x41, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f,
-	0x52, 0x45, 0x47, 0x49, 0x53, 0x54, 0x45, 0x52, 0x5f, 0x42, 0x52, 0x4f, 0x4b, 0x45, 0x52, 0x10,
-	0x04, 0x12, 0x15, 0x0a, 0x11, 0x55, 0x4e, 0x52, 0x45, 0x47, 0x49, 0x53, 0x54, 0x45, 0x52, 0x5f,
-	0x42, 0x52, 0x4f, 0x4b, 0x45, 0x52, 0x10, 0x05, 0x12, 0x1a, 0x0a, 0x16, 0x47, 0x45, 0x54, 0x5f,
-	0x52, 0x4f, 0x55, 0x54, 0x45, 0x49, 0x4e, 0x46, 0x4f, 0x5f, 0x42, 0x59, 0x5f, 0x54, 0x4f, 0x50,
-	0x49, 0x43, 0x10, 0x06, 0x12, 0x1b, 0x0a, 0x17, 0x47, 0x45, 0x54, 0x5f, 0x42, 0x52, 0x4f, 0x4b,
-	0x45, 0x52, 0x5f, 0x43, 0x4c, 0x55, 0x53, 0x54, 0x45, 0x52, 0x5f, 0x49, 0x4e, 0x46, 0x4f, 0x10,
-	0x07, 0x12, 0x1d, 0x0a, 0x19, 0x57, 0x49, 0x50, 0x45, 0x5f, 0x57, 0x52, 0x49, 0x54, 0x45, 0x5f,
-	0x50, 0x45, 0x52, 0x4d, 0x5f, 0x4f, 0x46, 0x5f, 0x42, 0x52, 0x4f, 0x4b, 0x45, 0x52, 0x10, 0x08,
-	0x12, 0x26, 0x0a, 0x22, 0x47, 0x45, 0x54, 0x5f, 0x41, 0x4c, 0x4c, 0x5f, 0x54, 0x4f, 0x50, 0x49,
-	0x43, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x5f, 0x46, 0x52, 0x4f, 0x4d, 0x5f, 0x4e, 0x41, 0x4d, 0x45,
-	0x53, 0x45, 0x52, 0x56, 0x45, 0x52, 0x10, 0x09, 0x12, 0x1b, 0x0a, 0x17, 0x44, 0x45, 0x4c, 0x45,
-	0x54, 0x45, 0x5f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x5f, 0x49, 0x4e, 0x5f, 0x4e, 0x41, 0x4d, 0x45,
-	0x53, 0x52, 0x56, 0x10, 0x0a, 0x12, 0x1b, 0x0a, 0x17, 0x47, 0x45, 0x54, 0x5f, 0x4b, 0x56, 0x4c,
-	0x49, 0x53, 0x54, 0x5f, 0x42, 0x59, 0x5f, 0x4e, 0x41, 0x4d, 0x45, 0x53, 0x50, 0x41, 0x43, 0x45,
-	0x10, 0x0b, 0x12, 0x19, 0x0a, 0x15, 0x47, 0x45, 0x54, 0x5f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x53,
-	0x5f, 0x42, 0x59, 0x5f, 0x43, 0x4c, 0x55, 0x53, 0x54, 0x45, 0x52, 0x10, 0x0c, 0x12, 0x21, 0x0a,
-	0x1d, 0x47, 0x45, 0x54, 0x5f, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x5f, 0x54, 0x4f, 0x50, 0x49,
-	0x43, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x5f, 0x46, 0x52, 0x4f, 0x4d, 0x5f, 0x4e, 0x53, 0x10, 0x0d,
-	0x12, 0x17, 0x0a, 0x13, 0x47, 0x45, 0x54, 0x5f, 0x55, 0x4e, 0x49, 0x54, 0x5f, 0x54, 0x4f, 0x50,
-	0x49, 0x43, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x0e, 0x12, 0x1f, 0x0a, 0x1b, 0x47, 0x45, 0x54,
-	0x5f, 0x48, 0x41, 0x53, 0x5f, 0x55, 0x4e, 0x49, 0x54, 0x5f, 0x53, 0x55, 0x42, 0x5f, 0x54, 0x4f,
-	0x50, 0x49, 0x43, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x0f, 0x12, 0x26, 0x0a, 0x22, 0x47, 0x45,
-	0x54, 0x5f, 0x48, 0x41, 0x53, 0x5f, 0x55, 0x4e, 0x49, 0x54, 0x5f, 0x53, 0x55, 0x42, 0x5f, 0x55,
-	0x4e, 0x55, 0x4e, 0x49, 0x54, 0x5f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x5f, 0x4c, 0x49, 0x53, 0x54,
-	0x10, 0x10, 0x12, 0x19, 0x0a, 0x15, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x4e, 0x41, 0x4d,
-	0x45, 0x53, 0x52, 0x56, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10, 0x11, 0x12, 0x16, 0x0a,
-	0x12, 0x47, 0x45, 0x54, 0x5f, 0x4e, 0x41, 0x4d, 0x45, 0x53, 0x52, 0x56, 0x5f, 0x43, 0x4f, 0x4e,
-	0x46, 0x49, 0x47, 0x10, 0x12, 0x2a, 0xa0, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53,
-	0x53, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x5f, 0x45, 0x52,
-	0x52, 0x4f, 0x52, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x5f,
-	0x42, 0x55, 0x53, 0x59, 0x10, 0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53,
-	0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x53, 0x55, 0x50, 0x50, 0x4f,
-	0x52, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x16, 0x0a, 0x12, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41,
-	0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x12, 0x13,
-	0x0a, 0x0f, 0x51, 0x55, 0x45, 0x52, 0x59, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e,
-	0x44, 0x10, 0x05, 0x12, 0x13, 0x0a, 0x0f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x5f, 0x4e, 0x4f, 0x54,
-	0x5f, 0x45, 0x58, 0x49, 0x53, 0x54, 0x10, 0x06, 0x32, 0x4a, 0x0a, 0x09, 0x52, 0x65, 0x6d, 0x6f,
-	0x74, 0x65, 0x52, 0x50, 0x43, 0x12, 0x3d, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
-	0x12, 0x15, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65,
-	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x1a, 0x15, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e,
-	0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x22, 0x00,
-	0x28, 0x01, 0x30, 0x01, 0x42, 0x0a, 0x5a, 0x08, 0x2e, 0x3b, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x07, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x22, 0x3a, 0x0a, 0x20, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06,
+	0x74, 0x6f, 0x70, 0x69, 0x63, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x74, 0x6f,
+	0x70, 0x69, 0x63, 0x73, 0x22, 0x3c, 0x0a, 0x22, 0x55, 0x6e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x6f,
+	0x70, 0x69, 0x63, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x74, 0x6f, 0x70, 0x69,
+	0x63, 0x73, 0x22, 0x3c, 0x0a, 0x24, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x54, 0x6f, 0x70, 0x69,
+	0x63, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f,
+	0x70, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63,
+	0x2a, 0xa5, 0x05, 0x0a, 0x0b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x11, 0x0a, 0x0d, 0x50, 0x55, 0x54, 0x5f, 0x4b, 0x56, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49,
+	0x47, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x47, 0x45, 0x54, 0x5f, 0x4b, 0x56, 0x5f, 0x43, 0x4f,
+	0x4e, 0x46, 0x49, 0x47, 0x10, 0x01, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45,
+	0x5f, 0x4b, 0x56, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12,
+	0x51, 0x55, 0x45, 0x52, 0x59, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49,
+	0x4f, 0x4e, 0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f, 0x52, 0x45, 0x47, 0x49, 0x53, 0x54, 0x45, 0x52,
+	0x5f, 0x42, 0x52, 0x4f, 0x4b, 0x45, 0x52, 0x10, 0x04, 0x12, 0x15, 0x0a, 0x11, 0x55, 0x4e, 0x52,
+	0x45, 0x47, 0x49, 0x53, 0x54, 0x45, 0x52, 0x5f, 0x42, 0x52, 0x4f, 0x4b, 0x45, 0x52, 0x10, 0x05,
+	0x12, 0x1a, 0x0a, 0x16, 0x47, 0x45, 0x54, 0x5f, 0x52, 0x4f, 0x55, 0x54, 0x45, 0x49, 0x4e, 0x46,
+	0x4f, 0x5f, 0x42, 0x59, 0x5f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x10, 0x06, 0x12, 0x1b, 0x0a, 0x17,
+	0x47, 0x45, 0x54, 0x5f, 0x42, 0x52, 0x4f, 0x4b, 0x45, 0x52, 0x5f, 0x43, 0x4c, 0x55, 0x53, 0x54,
+	0x45, 0x52, 0x5f, 0x49, 0x4e, 0x46, 0x4f, 0x10, 0x07, 0x12, 0x1d, 0x0a, 0x19, 0x57, 0x49, 0x50,
+	0x45, 0x5f, 0x57, 0x52, 0x49, 0x54, 0x45, 0x5f, 0x50, 0x45, 0x52, 0x4d, 0x5f, 0x4f, 0x46, 0x5f,
+	0x42, 0x52, 0x4f, 0x4b, 0x45, 0x52, 0x10, 0x08, 0x12, 0x26, 0x0a, 0x22, 0x47, 0x45, 0x54, 0x5f,
+	0x41, 0x4c, 0x4c, 0x5f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x5f, 0x46,
+	0x52, 0x4f, 0x4d, 0x5f, 0x4e, 0x41, 0x4d, 0x45, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52, 0x10, 0x09,
+	0x12, 0x1b, 0x0a, 0x17, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x5f, 0x54, 0x4f, 0x50, 0x49, 0x43,
+	0x5f, 0x49, 0x4e, 0x5f, 0x4e, 0x41, 0x4d, 0x45, 0x53, 0x52, 0x56, 0x10, 0x0a, 0x12, 0x1b, 0x0a,
+	0x17, 0x47, 0x45, 0x54, 0x5f, 0x4b, 0x56, 0x4c, 0x49, 0x53, 0x54, 0x5f, 0x42, 0x59, 0x5f, 0x4e,
+	0x41, 0x4d, 0x45, 0x53, 0x50, 0x41, 0x43, 0x45, 0x10, 0x0b, 0x12, 0x19, 0x0a, 0x15, 0x47, 0x45,
+	0x54, 0x5f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x53, 0x5f, 0x42, 0x59, 0x5f, 0x43, 0x4c, 0x55, 0x53,
+	0x54, 0x45, 0x52, 0x10, 0x0c, 0x12, 0x21, 0x0a, 0x1d, 0x47, 0x45, 0x54, 0x5f, 0x53, 0x59, 0x53,
+	0x54, 0x45, 0x4d, 0x5f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x5f, 0x46,
+	0x52, 0x4f, 0x4d, 0x5f, 0x4e, 0x53, 0x10, 0x0d, 0x12, 0x17, 0x0a, 0x13, 0x47, 0x45, 0x54, 0x5f,
+	0x55, 0x4e, 0x49, 0x54, 0x5f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10,
+	0x0e, 0x12, 0x1f, 0x0a, 0x1b, 0x47, 0x45, 0x54, 0x5f, 0x48, 0x41, 0x53, 0x5f, 0x55, 0x4e, 0x49,
+	0x54, 0x5f, 0x53, 0x55, 0x42, 0x5f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x5f, 0x4c, 0x49, 0x53, 0x54,
+	0x10, 0x0f, 0x12, 0x26, 0x0a, 0x22, 0x47, 0x45, 0x54, 0x5f, 0x48, 0x41, 0x53, 0x5f, 0x55, 0x4e,
+	0x49, 0x54, 0x5f, 0x53, 0x55, 0x42, 0x5f, 0x55, 0x4e, 0x55, 0x4e, 0x49, 0x54, 0x5f, 0x54, 0x4f,
+	0x50, 0x49, 0x43, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x10, 0x12, 0x19, 0x0a, 0x15, 0x55, 0x50,
+	0x44, 0x41, 0x54, 0x45, 0x5f, 0x4e, 0x41, 0x4d, 0x45, 0x53, 0x52, 0x56, 0x5f, 0x43, 0x4f, 0x4e,
+	0x46, 0x49, 0x47, 0x10, 0x11, 0x12, 0x16, 0x0a, 0x12, 0x47, 0x45, 0x54, 0x5f, 0x4e, 0x41, 0x4d,
+	0x45, 0x53, 0x52, 0x56, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10, 0x12, 0x12, 0x1c, 0x0a,
+	0x18, 0x41, 0x44, 0x44, 0x5f, 0x57, 0x52, 0x49, 0x54, 0x45, 0x5f, 0x50, 0x45, 0x52, 0x4d, 0x5f,
+	0x4f, 0x46, 0x5f, 0x42, 0x52, 0x4f, 0x4b, 0x45, 0x52, 0x10, 0x13, 0x12, 0x19, 0x0a, 0x15, 0x53,
+	0x55, 0x42, 0x53, 0x43, 0x52, 0x49, 0x42, 0x45, 0x5f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x5f, 0x52,
+	0x4f, 0x55, 0x54, 0x45, 0x10, 0x14, 0x12, 0x1b, 0x0a, 0x17, 0x55, 0x4e, 0x53, 0x55, 0x42, 0x53,
+	0x43, 0x52, 0x49, 0x42, 0x45, 0x5f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x5f, 0x52, 0x4f, 0x55, 0x54,
+	0x45, 0x10, 0x15, 0x12, 0x1e, 0x0a, 0x1a, 0x4e, 0x4f, 0x54, 0x49, 0x46, 0x59, 0x5f, 0x54, 0x4f,
+	0x50, 0x49, 0x43, 0x5f, 0x52, 0x4f, 0x55, 0x54, 0x45, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45,
+	0x44, 0x10, 0x16, 0x12, 0x13, 0x0a, 0x0f, 0x4e, 0x4f, 0x54, 0x49, 0x46, 0x59, 0x5f, 0x53, 0x48,
+	0x55, 0x54, 0x44, 0x4f, 0x57, 0x4e, 0x10, 0x17, 0x2a, 0xdf, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x55, 0x43,
+	0x43, 0x45, 0x53, 0x53, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d,
+	0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x59, 0x53, 0x54,
+	0x45, 0x4d, 0x5f, 0x42, 0x55, 0x53, 0x59, 0x10, 0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x52, 0x45, 0x51,
+	0x55, 0x45, 0x53, 0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x53, 0x55,
+	0x50, 0x50, 0x4f, 0x52, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x16, 0x0a, 0x12, 0x54, 0x52, 0x41,
+	0x4e, 0x53, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10,
+	0x04, 0x12, 0x13, 0x0a, 0x0f, 0x51, 0x55, 0x45, 0x52, 0x59, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46,
+	0x4f, 0x55, 0x4e, 0x44, 0x10, 0x05, 0x12, 0x13, 0x0a, 0x0f, 0x54, 0x4f, 0x50, 0x49, 0x43, 0x5f,
+	0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x58, 0x49, 0x53, 0x54, 0x10, 0x06, 0x12, 0x11, 0x0a, 0x0d, 0x4e,
+	0x4f, 0x5f, 0x50, 0x45, 0x52, 0x4d, 0x49, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x07, 0x12, 0x17,
+	0x0a, 0x13, 0x48, 0x45, 0x41, 0x44, 0x45, 0x52, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f,
+	0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x08, 0x12, 0x11, 0x0a, 0x0d, 0x41, 0x43, 0x43, 0x45, 0x53,
+	0x53, 0x5f, 0x44, 0x45, 0x4e, 0x49, 0x45, 0x44, 0x10, 0x09, 0x32, 0x4a, 0x0a, 0x09, 0x52, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x52, 0x50, 0x43, 0x12, 0x3d, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x12, 0x15, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x52, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x1a, 0x15, 0x2e, 0x63, 0x6f, 0x6d, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x0a, 0x5a, 0x08, 0x2e, 0x3b, 0x63, 0x6f, 0x6d, 0x6d,
+	0x6f, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1336,36 +1914,51 @@ func file_remote_proto_rawDescGZIP() []byte {
 }
 
 var file_remote_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_remote_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_remote_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_remote_proto_goTypes = []interface{}{
-	(RequestCode)(0),                            // 0: common.RequestCode
-	(ResponseCode)(0),                           // 1: common.ResponseCode
-	(*RemoteCommand)(nil),                       // 2: common.RemoteCommand
-	(*PutKVConfigRequestHeader)(nil),            // 3: common.PutKVConfigRequestHeader
-	(*GetKVConfigRequestHeader)(nil),            // 4: common.GetKVConfigRequestHeader
-	(*GetKVConfigResponseHeader)(nil),           // 5: common.GetKVConfigResponseHeader
-	(*DeleteKVConfigRequestHeader)(nil),         // 6: common.DeleteKVConfigRequestHeader
-	(*QueryDataVersionRequestHeader)(nil),       // 7: common.QueryDataVersionRequestHeader
-	(*QueryDataVersionResponseHeader)(nil),      // 8: common.QueryDataVersionResponseHeader
-	(*RegisterBrokerRequestHeader)(nil),         // 9: common.RegisterBrokerRequestHeader
-	(*RegisterBrokerResponseHeader)(nil),        // 10: common.RegisterBrokerResponseHeader
-	(*RegisterBrokerBody)(nil),                  // 11: common.RegisterBrokerBody
-	(*UnRegisterBrokerHeader)(nil),              // 12: common.UnRegisterBrokerHeader
-	(*GetRouteInfoRequestHeader)(nil),           // 13: common.GetRouteInfoRequestHeader
-	(*WipeWritePermOfBrokerRequestHeader)(nil),  // 14: common.WipeWritePermOfBrokerRequestHeader
-	(*WipeWritePermOfBrokerResponseHeader)(nil), // 15: common.WipeWritePermOfBrokerResponseHeader
-	(*DeleteTopicInNamesrvRequestHeader)(nil),   // 16: common.DeleteTopicInNamesrvRequestHeader
-	(*GetKVListByNamespaceRequestHeader)(nil),   // 17: common.GetKVListByNamespaceRequestHeader
-	(*GetTopicsByClusterRequestHeader)(nil),     // 18: common.GetTopicsByClusterRequestHeader
+	(RequestCode)(0),                             // 0: common.RequestCode
+	(ResponseCode)(0),                            // 1: common.ResponseCode
+	(*RemoteCommand)(nil),                        // 2: common.RemoteCommand
+	(*DataVersion)(nil),                          // 3: common.DataVersion
+	(*TopicConfig)(nil),                          // 4: common.TopicConfig
+	(*TopicConfigSerializeWrapper)(nil),          // 5: common.TopicConfigSerializeWrapper
+	(*PutKVConfigRequestHeader)(nil),             // 6: common.PutKVConfigRequestHeader
+	(*GetKVConfigRequestHeader)(nil),             // 7: common.GetKVConfigRequestHeader
+	(*GetKVConfigResponseHeader)(nil),            // 8: common.GetKVConfigResponseHeader
+	(*DeleteKVConfigRequestHeader)(nil),          // 9: common.DeleteKVConfigRequestHeader
+	(*QueryDataVersionRequestHeader)(nil),        // 10: common.QueryDataVersionRequestHeader
+	(*QueryDataVersionResponseHeader)(nil),       // 11: common.QueryDataVersionResponseHeader
+	(*RegisterBrokerRequestHeader)(nil),          // 12: common.RegisterBrokerRequestHeader
+	(*RegisterBrokerResponseHeader)(nil),         // 13: common.RegisterBrokerResponseHeader
+	(*RegisterBrokerBody)(nil),                   // 14: common.RegisterBrokerBody
+	(*UnRegisterBrokerHeader)(nil),               // 15: common.UnRegisterBrokerHeader
+	(*GetRouteInfoRequestHeader)(nil),            // 16: common.GetRouteInfoRequestHeader
+	(*WipeWritePermOfBrokerRequestHeader)(nil),   // 17: common.WipeWritePermOfBrokerRequestHeader
+	(*WipeWritePermOfBrokerResponseHeader)(nil),  // 18: common.WipeWritePermOfBrokerResponseHeader
+	(*AddWritePermOfBrokerRequestHeader)(nil),    // 19: common.AddWritePermOfBrokerRequestHeader
+	(*AddWritePermOfBrokerResponseHeader)(nil),   // 20: common.AddWritePermOfBrokerResponseHeader
+	(*DeleteTopicInNamesrvRequestHeader)(nil),    // 21: common.DeleteTopicInNamesrvRequestHeader
+	(*GetKVListByNamespaceRequestHeader)(nil),    // 22: common.GetKVListByNamespaceRequestHeader
+	(*GetTopicsByClusterRequestHeader)(nil),      // 23: common.GetTopicsByClusterRequestHeader
+	(*SubscribeTopicRouteRequestHeader)(nil),     // 24: common.SubscribeTopicRouteRequestHeader
+	(*UnSubscribeTopicRouteRequestHeader)(nil),   // 25: common.UnSubscribeTopicRouteRequestHeader
+	(*NotifyTopicRouteChangedRequestHeader)(nil), // 26: common.NotifyTopicRouteChangedRequestHeader
+	nil, // 27: common.RemoteCommand.ExtFieldsEntry
+	nil, // 28: common.TopicConfigSerializeWrapper.TopicConfigTableEntry
 }
 var file_remote_proto_depIdxs = []int32{
-	2, // 0: common.RemoteRPC.Process:input_type -> common.RemoteCommand
-	2, // 1: common.RemoteRPC.Process:output_type -> common.RemoteCommand
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	27, // 0: common.RemoteCommand.extFields:type_name -> common.RemoteCommand.ExtFieldsEntry
+	28, // 1: common.TopicConfigSerializeWrapper.topicConfigTable:type_name -> common.TopicConfigSerializeWrapper.TopicConfigTableEntry
+	3,  // 2: common.TopicConfigSerializeWrapper.dataVersion:type_name -> common.DataVersion
+	5,  // 3: common.RegisterBrokerBody.topicConfigSerializeWrapper:type_name -> common.TopicConfigSerializeWrapper
+	4,  // 4: common.TopicConfigSerializeWrapper.TopicConfigTableEntry.value:type_name -> common.TopicConfig
+	2,  // 5: common.RemoteRPC.Process:input_type -> common.RemoteCommand
+	2,  // 6: common.RemoteRPC.Process:output_type -> common.RemoteCommand
+	6,  // [6:7] is the sub-list for method output_type
+	5,  // [5:6] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_remote_proto_init() }
@@ -1387,7 +1980,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PutKVConfigRequestHeader); i {
+			switch v := v.(*DataVersion); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1399,7 +1992,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetKVConfigRequestHeader); i {
+			switch v := v.(*TopicConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1411,7 +2004,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetKVConfigResponseHeader); i {
+			switch v := v.(*TopicConfigSerializeWrapper); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1423,7 +2016,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteKVConfigRequestHeader); i {
+			switch v := v.(*PutKVConfigRequestHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1435,7 +2028,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryDataVersionRequestHeader); i {
+			switch v := v.(*GetKVConfigRequestHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1447,7 +2040,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryDataVersionResponseHeader); i {
+			switch v := v.(*GetKVConfigResponseHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1459,7 +2052,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RegisterBrokerRequestHeader); i {
+			switch v := v.(*DeleteKVConfigRequestHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1471,7 +2064,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RegisterBrokerResponseHeader); i {
+			switch v := v.(*QueryDataVersionRequestHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1483,7 +2076,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RegisterBrokerBody); i {
+			switch v := v.(*QueryDataVersionResponseHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1495,7 +2088,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UnRegisterBrokerHeader); i {
+			switch v := v.(*RegisterBrokerRequestHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1507,7 +2100,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetRouteInfoRequestHeader); i {
+			switch v := v.(*RegisterBrokerResponseHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1519,7 +2112,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WipeWritePermOfBrokerRequestHeader); i {
+			switch v := v.(*RegisterBrokerBody); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1531,7 +2124,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WipeWritePermOfBrokerResponseHeader); i {
+			switch v := v.(*UnRegisterBrokerHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1543,7 +2136,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteTopicInNamesrvRequestHeader); i {
+			switch v := v.(*GetRouteInfoRequestHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1555,7 +2148,7 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetKVListByNamespaceRequestHeader); i {
+			switch v := v.(*WipeWritePermOfBrokerRequestHeader); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1567,6 +2160,66 @@ func file_remote_proto_init() {
 			}
 		}
 		file_remote_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WipeWritePermOfBrokerResponseHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddWritePermOfBrokerRequestHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddWritePermOfBrokerResponseHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteTopicInNamesrvRequestHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetKVListByNamespaceRequestHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetTopicsByClusterRequestHeader); i {
 			case 0:
 				return &v.state
@@ -1578,6 +2231,42 @@ func file_remote_proto_init() {
 				return nil
 			}
 		}
+		file_remote_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeTopicRouteRequestHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnSubscribeTopicRouteRequestHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NotifyTopicRouteChangedRequestHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1585,7 +2274,7 @@ func file_remote_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_remote_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   17,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -1,7 +1,7 @@
 package common
 
 type BrokerData struct {
-	Cluster string
-	BrokerName string
-	BrokerAddrs map[int64]string
-}
\ No newline at end of file
+	Cluster     string           `json:"cluster"`
+	BrokerName  string           `json:"brokerName"`
+	BrokerAddrs map[int64]string `json:"brokerAddrs"`
+}
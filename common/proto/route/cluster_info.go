@@ -1,6 +1,6 @@
 package common
 
 type ClusterInfo struct {
-	BrokerAddrTable map[string]BrokerData
-	ClusterAddrTable map[string]map[string]bool
+	BrokerAddrTable  map[string]BrokerData `json:"brokerAddrTable"`
+	ClusterAddrTable map[string]StringSet  `json:"clusterAddrTable"`
 }
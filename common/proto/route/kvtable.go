@@ -1,5 +1,5 @@
 package common
 
 type KVTable struct {
-	Table map[string]string
+	Table map[string]string `json:"table"`
 }
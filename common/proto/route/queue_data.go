@@ -1,10 +1,11 @@
 package common
 
+// QueueData fields are declared in fastjson's order so that json.Marshal
+// writes the same bytes as the java name server.
 type QueueData struct {
-	BrokerName string
-	ReadQueueNums int
-	WriteQueueNums int
-	Perm int
-	TopicSysFlag int
+	BrokerName     string `json:"brokerName"`
+	Perm           int    `json:"perm"`
+	ReadQueueNums  int    `json:"readQueueNums"`
+	TopicSysFlag   int    `json:"topicSynFlag"` // the java field is misspelled
+	WriteQueueNums int    `json:"writeQueueNums"`
 }
-
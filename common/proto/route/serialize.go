@@ -0,0 +1,179 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+)
+
+// The java name server encodes its bodies with fastjson, which sorts the
+// fields by name and writes the Long keys of BrokerData.brokerAddrs without
+// quotes, e.g. {0:"127.0.0.1:10911"}. Clients parse that exact shape, so
+// route data and cluster info are encoded by hand instead of json.Marshal.
+
+func (b *BrokerData) encode(buf *bytes.Buffer) {
+	ids := make([]int64, 0, len(b.BrokerAddrs))
+	for id := range b.BrokerAddrs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	buf.WriteString(`{"brokerAddrs":{`)
+	for i, id := range ids {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(strconv.FormatInt(id, 10))
+		buf.WriteByte(':')
+		writeJSON(buf, b.BrokerAddrs[id])
+	}
+	buf.WriteString(`},"brokerName":`)
+	writeJSON(buf, b.BrokerName)
+	buf.WriteString(`,"cluster":`)
+	writeJSON(buf, b.Cluster)
+	buf.WriteByte('}')
+}
+
+func (t *TopicRouteData) Encode() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`{"brokerDatas":[`)
+	for i := range t.BrokerDatas {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		t.BrokerDatas[i].encode(&buf)
+	}
+	buf.WriteString(`],"filterServerTable":`)
+	if t.FilterServerTable == nil {
+		buf.WriteString("{}")
+	} else {
+		writeJSON(&buf, t.FilterServerTable)
+	}
+	if t.OrderTopicConf != "" {
+		buf.WriteString(`,"orderTopicConf":`)
+		writeJSON(&buf, t.OrderTopicConf)
+	}
+	buf.WriteString(`,"queueDatas":`)
+	if t.QueueDatas == nil {
+		buf.WriteString("[]")
+	} else {
+		writeJSON(&buf, t.QueueDatas)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+func DecodeTopicRouteData(data []byte) (*TopicRouteData, error) {
+	t := &TopicRouteData{}
+	if err := json.Unmarshal(QuoteNumericKeys(data), t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (c *ClusterInfo) Encode() []byte {
+	names := make([]string, 0, len(c.BrokerAddrTable))
+	for name := range c.BrokerAddrTable {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"brokerAddrTable":{`)
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeJSON(&buf, name)
+		buf.WriteByte(':')
+		brokerData := c.BrokerAddrTable[name]
+		brokerData.encode(&buf)
+	}
+	buf.WriteString(`},"clusterAddrTable":`)
+	if c.ClusterAddrTable == nil {
+		buf.WriteString("{}")
+	} else {
+		writeJSON(&buf, c.ClusterAddrTable)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+func DecodeClusterInfo(data []byte) (*ClusterInfo, error) {
+	c := &ClusterInfo{}
+	if err := json.Unmarshal(QuoteNumericKeys(data), c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (t *TopicList) Encode() []byte {
+	data, _ := json.Marshal(t)
+	return data
+}
+
+func (k *KVTable) Encode() []byte {
+	data, _ := json.Marshal(k)
+	return data
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) {
+	data, _ := json.Marshal(v)
+	buf.Write(data)
+}
+
+// QuoteNumericKeys turns the bare integer object keys written by fastjson
+// into standard json, anything else is copied as is.
+func QuoteNumericKeys(data []byte) []byte {
+	out := make([]byte, 0, len(data)+16)
+	inString, escaped, expectKey := false, false, false
+	var containers []byte
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out = append(out, c)
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			expectKey = false
+		case c == '{' || c == '[':
+			containers = append(containers, c)
+			expectKey = c == '{'
+		case c == '}' || c == ']':
+			if len(containers) > 0 {
+				containers = containers[:len(containers)-1]
+			}
+			expectKey = false
+		case c == ',':
+			expectKey = len(containers) > 0 && containers[len(containers)-1] == '{'
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case expectKey && (c == '-' || (c >= '0' && c <= '9')):
+			j := i + 1
+			for j < len(data) && data[j] >= '0' && data[j] <= '9' {
+				j++
+			}
+			out = append(out, '"')
+			out = append(out, data[i:j]...)
+			out = append(out, '"')
+			i = j - 1
+			expectKey = false
+			continue
+		default:
+			expectKey = false
+		}
+		out = append(out, c)
+	}
+	return out
+}
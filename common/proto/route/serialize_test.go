@@ -0,0 +1,116 @@
+package common
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readGolden(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func assertSameJSON(t *testing.T, want, got []byte) {
+	var w, g interface{}
+	if err := json.Unmarshal(QuoteNumericKeys(want), &w); err != nil {
+		t.Fatalf("invalid golden json: %v", err)
+	}
+	if err := json.Unmarshal(QuoteNumericKeys(got), &g); err != nil {
+		t.Fatalf("invalid encoded json %s: %v", got, err)
+	}
+	if !reflect.DeepEqual(w, g) {
+		t.Fatalf("json mismatch\nwant: %s\ngot:  %s", want, got)
+	}
+}
+
+func TestTopicRouteDataGolden(t *testing.T) {
+	golden := readGolden(t, "topic_route_data.json")
+
+	routeData, err := DecodeTopicRouteData(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &TopicRouteData{
+		OrderTopicConf: "broker-a:4",
+		QueueDatas: []QueueData{
+			{BrokerName: "broker-a", ReadQueueNums: 4, WriteQueueNums: 4, Perm: 6},
+		},
+		BrokerDatas: []BrokerData{
+			{
+				Cluster:     "DefaultCluster",
+				BrokerName:  "broker-a",
+				BrokerAddrs: map[int64]string{0: "192.168.0.1:10911", 1: "192.168.0.2:10911"},
+			},
+		},
+		FilterServerTable: map[string][]string{},
+	}
+	if !reflect.DeepEqual(want, routeData) {
+		t.Fatalf("decoded %+v, want %+v", routeData, want)
+	}
+
+	encoded := routeData.Encode()
+	if string(encoded) != string(golden) {
+		t.Fatalf("encoded json differs from golden\nwant: %s\ngot:  %s", golden, encoded)
+	}
+
+	// json.Marshal gives standard json, which java and go clients parse as well
+	standard, err := json.Marshal(routeData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameJSON(t, golden, standard)
+}
+
+func TestClusterInfoGolden(t *testing.T) {
+	golden := readGolden(t, "cluster_info.json")
+
+	clusterInfo, err := DecodeClusterInfo(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !clusterInfo.ClusterAddrTable["DefaultCluster"]["broker-b"] {
+		t.Fatalf("broker-b missing from cluster, got %v", clusterInfo.ClusterAddrTable)
+	}
+	if clusterInfo.BrokerAddrTable["broker-a"].BrokerAddrs[1] != "192.168.0.2:10911" {
+		t.Fatalf("slave of broker-a missing, got %v", clusterInfo.BrokerAddrTable)
+	}
+
+	assertSameJSON(t, golden, clusterInfo.Encode())
+}
+
+func TestTopicListGolden(t *testing.T) {
+	golden := readGolden(t, "topic_list.json")
+
+	topicList := &TopicList{}
+	if err := json.Unmarshal(golden, topicList); err != nil {
+		t.Fatal(err)
+	}
+	if len(topicList.TopicList) != 2 || !topicList.TopicList["TopicA"] {
+		t.Fatalf("decoded %v", topicList.TopicList)
+	}
+
+	assertSameJSON(t, golden, topicList.Encode())
+}
+
+func TestQuoteNumericKeys(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{`{0:"a",1:"b"}`, `{"0":"a","1":"b"}`},
+		{`{"k":[1,2,{-1:"x"}]}`, `{"k":[1,2,{"-1":"x"}]}`},
+		{`{"s":"{0:\"a\"}"}`, `{"s":"{0:\"a\"}"}`},
+		{`{ 10 : "a" }`, `{ "10" : "a" }`},
+	}
+	for _, c := range cases {
+		if got := string(QuoteNumericKeys([]byte(c.in))); got != c.want {
+			t.Errorf("QuoteNumericKeys(%s) = %s, want %s", c.in, got, c.want)
+		}
+	}
+}
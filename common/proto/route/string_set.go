@@ -0,0 +1,30 @@
+package common
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// StringSet is a java Set<String>, it is encoded as a json array.
+type StringSet map[string]bool
+
+func (s StringSet) MarshalJSON() ([]byte, error) {
+	list := make([]string, 0, len(s))
+	for k := range s {
+		list = append(list, k)
+	}
+	sort.Strings(list)
+	return json.Marshal(list)
+}
+
+func (s *StringSet) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*s = make(StringSet, len(list))
+	for _, k := range list {
+		(*s)[k] = true
+	}
+	return nil
+}
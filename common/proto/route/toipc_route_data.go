@@ -1,25 +1,8 @@
 package common
 
 type TopicRouteData struct {
-	orderTopicConf string
-	queueDataList *[]QueueData
-	brokerDataList *[]BrokerData
-	filterServerTable *map[string][]string
+	OrderTopicConf    string              `json:"orderTopicConf,omitempty"`
+	QueueDatas        []QueueData         `json:"queueDatas"`
+	BrokerDatas       []BrokerData        `json:"brokerDatas"`
+	FilterServerTable map[string][]string `json:"filterServerTable"`
 }
-
-func (t *TopicRouteData) SetBrokerDataList(l *[]BrokerData) {
-	t.brokerDataList = l
-}
-
-func (t *TopicRouteData) GetBrokerDataList() *[]BrokerData {
-	return t.brokerDataList
-}
-
-func (t *TopicRouteData) SetFilterServerTable(f *map[string][]string) {
-	t.filterServerTable = f
-}
-
-func (t *TopicRouteData) SetQueueDataList(l *[]QueueData) {
-	t.queueDataList = l
-}
-
@@ -1,6 +1,6 @@
 package common
 
 type TopicList struct {
-	BrokerAddr string
-	TopicList map[string]bool
-}
\ No newline at end of file
+	BrokerAddr string    `json:"brokerAddr,omitempty"`
+	TopicList  StringSet `json:"topicList"`
+}
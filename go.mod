@@ -4,7 +4,9 @@ go 1.14
 
 require (
 	github.com/BurntSushi/toml v0.3.1
-	github.com/golang/protobuf v1.4.1
+	github.com/golang/protobuf v1.4.2
+	github.com/prometheus/client_golang v1.7.1
+	github.com/prometheus/common v0.10.0
 	go.uber.org/atomic v1.6.0
 	go.uber.org/zap v1.15.0
 	golang.org/x/crypto v0.0.0-20190510104115-cbcb75029529
@@ -1,6 +1,7 @@
 package logging
 
 import (
+	"fmt"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -8,7 +9,14 @@ import (
 	"time"
 )
 
-var Log *zap.Logger
+var (
+	Log = zap.NewNop()
+	// AuditLog records security relevant events, e.g. denied requests
+	AuditLog = zap.NewNop()
+
+	// level of Log, it can be changed while running
+	level = zap.NewAtomicLevel()
+)
 
 func Init(filename string, logLevel string) {
 	hook := lumberjack.Logger{
@@ -19,16 +27,8 @@ func Init(filename string, logLevel string) {
 		Compress:   true, 		// 是否压缩
 	}
 
-	var level zapcore.Level
-	switch logLevel {
-	case "debug":
-		level = zap.DebugLevel
-	case "info":
-		level = zap.InfoLevel
-	case "error":
-		level = zap.ErrorLevel
-	default:
-		level = zap.InfoLevel
+	if err := SetLevel(logLevel); err != nil {
+		level.SetLevel(zap.InfoLevel)
 	}
 
 	encoderConfig := zap.NewProductionEncoderConfig()
@@ -43,6 +43,44 @@ func Init(filename string, logLevel string) {
 	Log = zap.New(core)
 }
 
+// SetLevel changes the level of Log to one of debug, info, warn and error.
+func SetLevel(logLevel string) error {
+	switch logLevel {
+	case "debug":
+		level.SetLevel(zap.DebugLevel)
+	case "info":
+		level.SetLevel(zap.InfoLevel)
+	case "warn":
+		level.SetLevel(zap.WarnLevel)
+	case "error":
+		level.SetLevel(zap.ErrorLevel)
+	default:
+		return fmt.Errorf("unknown log level: %s", logLevel)
+	}
+	return nil
+}
+
+// InitAudit writes AuditLog to its own file, apart from the server log.
+func InitAudit(filename string) {
+	hook := lumberjack.Logger{
+		Filename:   filename,
+		MaxSize:    10,
+		MaxBackups: 3,
+		MaxAge:     28,
+		Compress:   true,
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = timeEncoder
+
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(encoderConfig),
+		zapcore.AddSync(&hook),
+		zap.InfoLevel,
+	)
+	AuditLog = zap.New(core)
+}
+
 func timeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 	type appendTimeEncoder interface {
 		AppendTimeLayout(time.Time, string)
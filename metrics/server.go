@@ -0,0 +1,75 @@
+// Package metrics serves the metrics of a prometheus registry over http.
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+	"net"
+	"net/http"
+	. "rocketmq-go/logging"
+	"time"
+)
+
+const (
+	shutdownTimeout = 5 * time.Second
+)
+
+// Server serves the metrics of a gatherer at /metrics.
+type Server struct {
+	addr     string
+	gatherer prometheus.Gatherer
+	listener net.Listener
+	srv      *http.Server
+}
+
+func NewServer(addr string, gatherer prometheus.Gatherer) *Server {
+	return &Server{
+		addr:     addr,
+		gatherer: gatherer,
+	}
+}
+
+func (s *Server) Start() error {
+	listen, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listen
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(s.gatherer, promhttp.HandlerOpts{}))
+	s.srv = &http.Server{Handler: mux}
+
+	go func() {
+		if err := s.srv.Serve(listen); err != nil && err != http.ErrServerClosed {
+			Log.Error("Metrics server exit", zap.Error(err))
+		}
+	}()
+	Log.Info("Metrics server started", zap.String("addr", listen.Addr().String()))
+	return nil
+}
+
+// Addr returns the address the server listens on, it is only valid after
+// Start.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	_ = s.Shutdown(ctx)
+}
+
+// Shutdown stops taking connections and waits for the requests in flight
+// until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.srv.Shutdown(ctx)
+	if err != nil {
+		Log.Warn("Metrics server shutdown", zap.Error(err))
+	}
+	Log.Info("Metrics server stopped")
+	return err
+}
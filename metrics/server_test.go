@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	r := prometheus.NewRegistry()
+	expired := prometheus.NewCounter(prometheus.CounterOpts{Name: "expired_total", Help: "Expired."})
+	r.MustRegister(expired)
+	expired.Inc()
+	s := NewServer("127.0.0.1:0", r)
+	s.Start()
+	defer s.Stop()
+
+	resp, err := http.Get("http://" + s.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") ||
+		!strings.Contains(string(body), "expired_total 1\n") {
+		t.Errorf("content type %q, body\n%s", resp.Header.Get("Content-Type"), body)
+	}
+}
@@ -0,0 +1,309 @@
+// Package admin serves an HTTP/JSON API over the name server state, for
+// operators to inspect and fix a running name server with curl.
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go.uber.org/zap"
+	"net"
+	"net/http"
+	. "rocketmq-go/common/proto/route"
+	. "rocketmq-go/logging"
+	. "rocketmq-go/namesrv/config"
+	. "rocketmq-go/namesrv/control"
+	"strings"
+	"time"
+)
+
+const (
+	shutdownTimeout = 5 * time.Second
+)
+
+// Server exposes
+//
+//	GET    /cluster                          cluster info
+//	GET    /topics                           all topics
+//	GET    /topics/{topic}/route             route of a topic
+//	GET    /kv/{namespace}/{key}             KV config value
+//	PUT    /kv/{namespace}/{key}             set a KV config value, the body is {"value":"..."}
+//	DELETE /kv/{namespace}/{key}             delete a KV config value
+//	POST   /brokers/unregister               unregister a broker
+//	POST   /brokers/{brokerName}/wipe-write-perm
+//	GET    /config                           name server config
+//	PUT    /config                           update the config, the body is {"key":"value",...}
+type Server struct {
+	Control *Control
+
+	addr     string
+	listener net.Listener
+	srv      *http.Server
+}
+
+func NewServer(addr string, control *Control) *Server {
+	return &Server{
+		Control: control,
+		addr:    addr,
+	}
+}
+
+func (s *Server) Start() error {
+	listen, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listen
+	s.srv = &http.Server{Handler: s.handler()}
+
+	go func() {
+		if err := s.srv.Serve(listen); err != nil && err != http.ErrServerClosed {
+			Log.Error("Admin server exit", zap.Error(err))
+		}
+	}()
+	Log.Info("Admin server started", zap.String("addr", listen.Addr().String()))
+	return nil
+}
+
+// Addr returns the address the server listens on, it is only valid after
+// Start.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	_ = s.Shutdown(ctx)
+}
+
+// Shutdown stops taking connections and waits for the requests in flight
+// until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.srv.Shutdown(ctx)
+	if err != nil {
+		Log.Warn("Admin server shutdown", zap.Error(err))
+	}
+	Log.Info("Admin server stopped")
+	return err
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cluster", s.cluster)
+	mux.HandleFunc("/topics", s.topics)
+	mux.HandleFunc("/topics/", s.topicRoute)
+	mux.HandleFunc("/kv/", s.kv)
+	mux.HandleFunc("/brokers/unregister", s.unRegisterBroker)
+	mux.HandleFunc("/brokers/", s.wipeWritePerm)
+	mux.HandleFunc("/config", s.config)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not found")
+	})
+	return mux
+}
+
+// pathParams returns the segments of path after prefix, ok is false when
+// their number is not n.
+func pathParams(path string, prefix string, n int) ([]string, bool) {
+	params := strings.Split(strings.Trim(strings.TrimPrefix(path, prefix), "/"), "/")
+	if len(params) != n {
+		return nil, false
+	}
+	for _, param := range params {
+		if param == "" {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeRawJSON(w, status, data)
+}
+
+func writeRawJSON(w http.ResponseWriter, status int, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	data, _ := json.Marshal(map[string]string{"error": message})
+	writeRawJSON(w, status, data)
+}
+
+// audit writes a call changing the name server state to the log and the
+// audit log.
+func audit(r *http.Request, msg string, fields ...zap.Field) {
+	fields = append([]zap.Field{zap.String("addr", r.RemoteAddr)}, fields...)
+	Log.Info(msg, fields...)
+	AuditLog.Info(msg, fields...)
+}
+
+func (s *Server) cluster(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	// The route body keeps the fastjson integer keys, not valid json
+	writeRawJSON(w, http.StatusOK, QuoteNumericKeys(s.Control.RouteInfo.GetAllClusterInfo()))
+}
+
+func (s *Server) topics(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	writeRawJSON(w, http.StatusOK, s.Control.RouteInfo.GetAllTopicList())
+}
+
+func (s *Server) topicRoute(w http.ResponseWriter, r *http.Request) {
+	params, ok := pathParams(r.URL.Path, "/topics/", 2)
+	if !ok || params[1] != "route" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	topicRouteData := s.Control.PickupTopicRouteData(params[0])
+	if topicRouteData == nil {
+		writeError(w, http.StatusNotFound, "no topic route info in name server for the topic: "+params[0])
+		return
+	}
+	writeRawJSON(w, http.StatusOK, QuoteNumericKeys(topicRouteData.Encode()))
+}
+
+func (s *Server) kv(w http.ResponseWriter, r *http.Request) {
+	params, ok := pathParams(r.URL.Path, "/kv/", 2)
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if !allowMethods(w, r, http.MethodGet, http.MethodPut, http.MethodDelete) {
+		return
+	}
+	namespace, key := params[0], params[1]
+	kvConfig := s.Control.KVConfig
+
+	switch r.Method {
+	case http.MethodGet:
+		value := kvConfig.GetKVConfig(namespace, key)
+		if value == "" {
+			writeError(w, http.StatusNotFound, "no config item, namespace: "+namespace+" key: "+key)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"namespace": namespace, "key": key, "value": value})
+	case http.MethodPut:
+		var body struct {
+			Value string `json:"value"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Value == "" {
+			writeError(w, http.StatusBadRequest, `body must be {"value":"..."}`)
+			return
+		}
+		kvConfig.PutKVConfig(namespace, key, body.Value)
+		audit(r, "Admin put kv config", zap.String("namespace", namespace), zap.String("key", key))
+		writeJSON(w, http.StatusOK, map[string]string{"namespace": namespace, "key": key, "value": body.Value})
+	case http.MethodDelete:
+		kvConfig.DeleteKVConfig(namespace, key)
+		audit(r, "Admin delete kv config", zap.String("namespace", namespace), zap.String("key", key))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+type unRegisterBrokerRequest struct {
+	ClusterName string `json:"clusterName"`
+	BrokerAddr  string `json:"brokerAddr"`
+	BrokerName  string `json:"brokerName"`
+	BrokerId    int64  `json:"brokerId"`
+}
+
+func (s *Server) unRegisterBroker(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var req unRegisterBrokerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "decode request failed: "+err.Error())
+		return
+	}
+	if req.ClusterName == "" || req.BrokerAddr == "" || req.BrokerName == "" {
+		writeError(w, http.StatusBadRequest, "clusterName, brokerAddr and brokerName are required")
+		return
+	}
+
+	s.Control.RouteInfo.UnRegisterBroker(req.ClusterName, req.BrokerAddr, req.BrokerName, req.BrokerId)
+	audit(r, "Admin unregister broker",
+		zap.String("brokerName", req.BrokerName), zap.String("brokerAddr", req.BrokerAddr))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) wipeWritePerm(w http.ResponseWriter, r *http.Request) {
+	params, ok := pathParams(r.URL.Path, "/brokers/", 2)
+	if !ok || params[1] != "wipe-write-perm" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	wipeTopicCnt := s.Control.RouteInfo.WipeWritePermOfBroker(params[0])
+	audit(r, "Admin wipe write perm of broker",
+		zap.String("brokerName", params[0]), zap.Int("wipeTopicCount", wipeTopicCnt))
+	writeJSON(w, http.StatusOK, map[string]int{"wipeTopicCount": wipeTopicCnt})
+}
+
+func (s *Server) config(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPut) {
+		return
+	}
+	// The config changes at runtime, only go through its locked methods
+	conf := s.Control.NameSrvConf
+	if r.Method == http.MethodPut {
+		var properties map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&properties); err != nil {
+			writeError(w, http.StatusBadRequest, "body must be a json object of strings: "+err.Error())
+			return
+		}
+		if err := s.Control.UpdateConfig(properties); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, ErrStaticKey) {
+				status = http.StatusForbidden
+			}
+			writeError(w, status, err.Error())
+			return
+		}
+		if err := conf.Persist(); err != nil {
+			Log.Error("persist name server config failed", zap.Error(err))
+			writeError(w, http.StatusInternalServerError, "config updated but persist failed: "+err.Error())
+			return
+		}
+		audit(r, "Admin update config", zap.Any("properties", properties))
+	}
+
+	properties, err := ParseProperties(conf.Snapshot().Properties())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, properties)
+}
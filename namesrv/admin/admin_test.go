@@ -0,0 +1,183 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	pb "rocketmq-go/common/proto"
+	"rocketmq-go/logging"
+	"rocketmq-go/namesrv/config"
+	"rocketmq-go/namesrv/control"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T) (*Server, *httptest.Server) {
+	dir, err := ioutil.TempDir("", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	conf := config.Default()
+	conf.KVConfigPath = filepath.Join(dir, "kvConfig.json")
+	conf.ConfigStorePath = filepath.Join(dir, "namesrv.properties")
+	conf.ListenAddr = "127.0.0.1:0"
+	ctl := control.NewControl(conf)
+	s := NewServer("127.0.0.1:0", ctl)
+	ts := httptest.NewServer(s.handler())
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func do(t *testing.T, method string, url string, body string) (int, map[string]interface{}) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := ioutil.ReadAll(resp.Body)
+	var v map[string]interface{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &v); err != nil {
+			t.Fatalf("%s %s: invalid json %s", method, url, data)
+		}
+	}
+	return resp.StatusCode, v
+}
+
+func TestAdminAPI(t *testing.T) {
+	s, ts := newTestServer(t)
+	s.Control.RouteInfo.RegisterBroker("DefaultCluster", "127.0.0.1:10911", "broker-a", 0, "",
+		&pb.TopicConfigSerializeWrapper{
+			TopicConfigTable: map[string]*pb.TopicConfig{
+				"TopicTest": {TopicName: "TopicTest", ReadQueueNums: 4, WriteQueueNums: 4, Perm: 6},
+			},
+			DataVersion: &pb.DataVersion{Timestamp: 1, Counter: 1},
+		}, nil, "127.0.0.1:50000")
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodGet, "/cluster", "", http.StatusOK},
+		{http.MethodGet, "/topics", "", http.StatusOK},
+		{http.MethodGet, "/topics/TopicTest/route", "", http.StatusOK},
+		{http.MethodGet, "/topics/NoSuchTopic/route", "", http.StatusNotFound},
+		{http.MethodPost, "/topics", "", http.StatusMethodNotAllowed},
+		{http.MethodPut, "/kv/ns/key", `{"value":"v"}`, http.StatusOK},
+		{http.MethodGet, "/kv/ns/key", "", http.StatusOK},
+		{http.MethodDelete, "/kv/ns/key", "", http.StatusNoContent},
+		{http.MethodGet, "/kv/ns/key", "", http.StatusNotFound},
+		{http.MethodPut, "/kv/ns/key", `not json`, http.StatusBadRequest},
+		{http.MethodPost, "/brokers/broker-a/wipe-write-perm", "", http.StatusOK},
+		{http.MethodGet, "/config", "", http.StatusOK},
+		{http.MethodPut, "/config", `{"orderMessageEnable":"true"}`, http.StatusOK},
+		{http.MethodPut, "/config", `{"listenAddr":"0.0.0.0:1"}`, http.StatusForbidden},
+		{http.MethodPut, "/config", `{"noSuchKey":"1"}`, http.StatusBadRequest},
+		{http.MethodPost, "/brokers/unregister",
+			`{"clusterName":"DefaultCluster","brokerAddr":"127.0.0.1:10911","brokerName":"broker-a","brokerId":0}`,
+			http.StatusNoContent},
+		{http.MethodGet, "/topics/TopicTest/route", "", http.StatusNotFound},
+		{http.MethodGet, "/no/such/path", "", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		status, body := do(t, test.method, ts.URL+test.path, test.body)
+		if status != test.status {
+			t.Errorf("%s %s: status = %d, want %d, body %v", test.method, test.path, status, test.status, body)
+		}
+	}
+
+	if !s.Control.NameSrvConf.Snapshot().OrderMessageEnable {
+		t.Error("config update not applied")
+	}
+}
+
+func TestWipeWritePermCount(t *testing.T) {
+	s, ts := newTestServer(t)
+	s.Control.RouteInfo.RegisterBroker("DefaultCluster", "127.0.0.1:10911", "broker-a", 0, "",
+		&pb.TopicConfigSerializeWrapper{
+			TopicConfigTable: map[string]*pb.TopicConfig{
+				"TopicA": {TopicName: "TopicA", Perm: 6},
+				"TopicB": {TopicName: "TopicB", Perm: 6},
+			},
+			DataVersion: &pb.DataVersion{Timestamp: 1, Counter: 1},
+		}, nil, "127.0.0.1:50000")
+
+	status, body := do(t, http.MethodPost, ts.URL+"/brokers/broker-a/wipe-write-perm", "")
+	if status != http.StatusOK || body["wipeTopicCount"] != float64(2) {
+		t.Fatalf("status = %d, body = %v", status, body)
+	}
+}
+
+func TestAuditMutatingCalls(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	auditLog := logging.AuditLog
+	logging.AuditLog = zap.New(core)
+	defer func() { logging.AuditLog = auditLog }()
+
+	_, ts := newTestServer(t)
+	do(t, http.MethodGet, ts.URL+"/kv/ns/key", "")
+	do(t, http.MethodGet, ts.URL+"/config", "")
+	do(t, http.MethodPut, ts.URL+"/kv/ns/key", `{"value":"v"}`)
+	do(t, http.MethodDelete, ts.URL+"/kv/ns/key", "")
+	do(t, http.MethodPut, ts.URL+"/config", `{"orderMessageEnable":"true"}`)
+
+	var messages []string
+	for _, entry := range logs.All() {
+		if entry.ContextMap()["addr"] == "" {
+			t.Errorf("audit entry %q without the client address", entry.Message)
+		}
+		messages = append(messages, entry.Message)
+	}
+	want := "Admin put kv config,Admin delete kv config,Admin update config"
+	if got := strings.Join(messages, ","); got != want {
+		t.Fatalf("audit log = %s, want %s", got, want)
+	}
+}
+
+func TestConfigUpdateWhileRouting(t *testing.T) {
+	s, ts := newTestServer(t)
+	s.Control.RouteInfo.RegisterBroker("DefaultCluster", "127.0.0.1:10911", "broker-a", 0, "",
+		&pb.TopicConfigSerializeWrapper{
+			TopicConfigTable: map[string]*pb.TopicConfig{
+				"TopicTest": {TopicName: "TopicTest", ReadQueueNums: 4, WriteQueueNums: 4, Perm: 6},
+			},
+			DataVersion: &pb.DataVersion{Timestamp: 1, Counter: 1},
+		}, nil, "127.0.0.1:50000")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			body := fmt.Sprintf(`{"orderMessageEnable":"%t"}`, i%2 == 0)
+			req, _ := http.NewRequest(http.MethodPut, ts.URL+"/config", strings.NewReader(body))
+			if resp, err := http.DefaultClient.Do(req); err == nil {
+				resp.Body.Close()
+			}
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		if status, body := do(t, http.MethodGet, ts.URL+"/topics/TopicTest/route", ""); status != http.StatusOK {
+			t.Fatalf("route while updating the config: status = %d, body %v", status, body)
+		}
+	}
+	<-done
+
+	if status, body := do(t, http.MethodGet, ts.URL+"/config", ""); status != http.StatusOK || body["orderMessageEnable"] != "false" {
+		t.Fatalf("config after the updates: status = %d, body %v", status, body)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"go.uber.org/zap"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"rocketmq-go/logging"
+	"rocketmq-go/namesrv"
+	"rocketmq-go/namesrv/config"
+	"syscall"
+	"time"
+)
+
+var (
+	confPath string
+	logPath string
+	configFlags config.Flags
+)
+
+func init() {
+	// $ROCKETMQ_HOME is the default of -c and -log
+	if rocketHome := os.Getenv("ROCKETMQ_HOME"); rocketHome != "" {
+		confPath = rocketHome + "/conf"
+		logPath = rocketHome + "/log"
+	}
+	flag.StringVar(&confPath, "c", confPath, "set configuration file")
+	flag.StringVar(&logPath, "log", logPath, "set log file")
+	configFlags = config.BindFlags(flag.CommandLine)
+}
+
+func main() {
+	flag.Parse()
+	if confPath == "" || logPath == "" {
+		log.Fatal("please set env $ROCKETMQ_HOME or flags -c and -log")
+	}
+	initConfAndLog()
+	logging.Init(logPath, "debug")
+
+	stopChan := make(chan os.Signal, 1)
+	signal.Notify(stopChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL)
+
+	conf, err := config.Load(confPath, configFlags)
+	if err != nil {
+		log.Fatalf("load config failed: %v", err)
+	}
+
+	srv, err := namesrv.NewServer(conf)
+	if err != nil {
+		log.Fatalf("create name server failed: %v", err)
+	}
+	if err := srv.Start(context.Background()); err != nil {
+		log.Fatalf("start name server failed: %v", err)
+	}
+
+	// SIGHUP reloads namesrv.toml instead of stopping the server
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	go func() {
+		for range reloadChan {
+			reload(srv)
+		}
+	}()
+
+	sig := <- stopChan
+	logging.Log.Sugar().Debugf("Signal: %v", sig)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(srv.Control().NameSrvConf.Snapshot().ShutdownTimeoutSeconds) * time.Second)
+	defer cancel()
+	_ = srv.Shutdown(ctx)
+}
+
+// reload loads namesrv.toml again, a file which fails to load or validate
+// is rejected and the running config kept.
+func reload(srv *namesrv.Server) {
+	conf, err := config.Load(confPath, configFlags)
+	if err != nil {
+		logging.Log.Error("Reload config failed, keep the running one",
+			zap.String("path", confPath),
+			zap.Error(err))
+		return
+	}
+	srv.Reload(conf)
+}
+
+func exists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+
+	return false, err
+}
+
+func isDir(path string) bool {
+	s, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return s.IsDir()
+}
+
+func getCurPath() string {
+	dir, err := filepath.Abs(filepath.Dir(os.Args[0]))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return dir
+}
+
+func initConfAndLog() {
+	if !isDir(confPath) {
+		log.Fatalf("config path is not dir: %s", confPath)
+	}
+
+	if !isDir(logPath) {
+		log.Fatalf("log path is not dir: %s", logPath)
+	}
+
+	confPath, _ = filepath.Abs(confPath)
+	confPath = confPath + string(filepath.Separator) + "namesrv.toml"
+	if ok, err := exists(confPath); !ok {
+		log.Fatal(err)
+	}
+
+	logPath, _ = filepath.Abs(logPath)
+	logPath = logPath + string(filepath.Separator) + "namesrv.log"
+}
\ No newline at end of file
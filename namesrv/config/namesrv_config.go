@@ -1,35 +1,113 @@
 package config
 
 import (
+	"fmt"
 	"github.com/BurntSushi/toml"
+	"io/ioutil"
 	"path/filepath"
-	"sync"
-)
-
-var (
-	cfg * Config
-	once sync.Once
+	"strings"
 )
 
 type Config struct {
-	RocketmqHome string `toml:"rocketMQHome"`
+	RocketmqHome string `toml:"rocketmqHome"`
 	KVConfigPath string `toml:"kvConfigPath"`
 	ConfigStorePath string `toml:"configStorePath"`
 	ClusterTest bool `toml:"clusterTest"`
 	OrderMessageEnable bool `toml:"orderMessageEnable"`
 	ListenAddr string `toml:"listenAddr"`
+	NativeListenAddr string `toml:"nativeListenAddr"`
+	AdminListenAddr string `toml:"adminListenAddr"`
+	MetricsListenAddr string `toml:"metricsListenAddr"`
+	ShutdownTimeoutSeconds int `toml:"shutdownTimeoutSeconds"`
+	LogLevel string `toml:"logLevel"`
+	BrokerExpiredTimeMills int64 `toml:"brokerExpiredTimeMills"`
+	ScanNotActiveBrokerIntervalSeconds int `toml:"scanNotActiveBrokerIntervalSeconds"`
+	PrintKVConfigIntervalSeconds int `toml:"printKVConfigIntervalSeconds"`
+	DefaultThreadPoolNums int `toml:"defaultThreadPoolNums"`
+	DefaultThreadPoolQueueCapacity int `toml:"defaultThreadPoolQueueCapacity"`
+	BrokerThreadPoolNums int `toml:"brokerThreadPoolNums"`
+	BrokerThreadPoolQueueCapacity int `toml:"brokerThreadPoolQueueCapacity"`
+	TLSEnable bool `toml:"tlsEnable"`
+	TLSCertPath string `toml:"tlsCertPath"`
+	TLSKeyPath string `toml:"tlsKeyPath"`
+	TLSCAPath string `toml:"tlsCaPath"`
+	TLSNeedClientAuth bool `toml:"tlsNeedClientAuth"`
+	TLSAllowedSubjects []string `toml:"tlsAllowedSubjects"`
+	TLSReloadIntervalSeconds int `toml:"tlsReloadIntervalSeconds"`
+	ACLEnable bool `toml:"aclEnable"`
+	ACLConfigPath string `toml:"aclConfigPath"`
+	ACLAuditLogPath string `toml:"aclAuditLogPath"`
+
+	// stored are the values updated at runtime by key, formatted, which
+	// Persist writes to ConfigStorePath
+	stored map[string]string
 }
 
-func NewConfig(confPath string) *Config {
-	once.Do(func() {
-		filePath, err := filepath.Abs(confPath)
-		if err != nil {
-			panic(err)
-		}
+// Load reads the config in layers, each over the ones before: the
+// defaults, the runtime updates persisted to ConfigStorePath, the file at
+// confPath, the ROCKETMQ_* environment variables and flags, which may be
+// nil. Unknown keys in the file and invalid values are errors.
+func Load(confPath string, flags Flags) (*Config, error) {
+	filePath, err := filepath.Abs(confPath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// ConfigStorePath is set by the layers over the store
+	probe := Default()
+	if err := probe.loadFile(filePath, string(data), flags); err != nil {
+		return nil, err
+	}
+
+	conf := Default()
+	conf.ConfigStorePath = probe.ConfigStorePath
+	if err := conf.LoadStore(); err != nil {
+		return nil, fmt.Errorf("load config store: %w", err)
+	}
+	if err := conf.loadFile(filePath, string(data), flags); err != nil {
+		return nil, err
+	}
 
-		if _ , err := toml.DecodeFile(filePath, &cfg); err != nil {
-			panic(err)
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// loadFile applies the file content read from filePath, then the
+// environment variables and flags.
+func (c *Config) loadFile(filePath string, content string, flags Flags) error {
+	md, err := toml.Decode(content, c)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filePath, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
 		}
-	})
-	return cfg
-}
\ No newline at end of file
+		return fmt.Errorf("unknown config keys in %s: %s", filePath, strings.Join(keys, ", "))
+	}
+	return c.override(flags)
+}
+
+// Default returns the config of a name server which sets nothing.
+func Default() *Config {
+	return &Config{
+		ListenAddr: "0.0.0.0:9876",
+		ShutdownTimeoutSeconds: 30,
+		LogLevel: "debug",
+		BrokerExpiredTimeMills: 1000 * 5,
+		ScanNotActiveBrokerIntervalSeconds: 10,
+		PrintKVConfigIntervalSeconds: 10 * 60,
+		DefaultThreadPoolNums: 8,
+		DefaultThreadPoolQueueCapacity: 10000,
+		BrokerThreadPoolNums: 4,
+		BrokerThreadPoolQueueCapacity: 10000,
+		TLSReloadIntervalSeconds: 10,
+	}
+}
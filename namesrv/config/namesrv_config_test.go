@@ -0,0 +1,167 @@
+package config
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func tempConf(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	confPath := filepath.Join(dir, "namesrv.toml")
+	writeConf(t, confPath, content)
+	return confPath
+}
+
+func TestLoadDefaults(t *testing.T) {
+	c, err := Load(tempConf(t, "orderMessageEnable = true\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.OrderMessageEnable || c.ListenAddr != "0.0.0.0:9876" || c.DefaultThreadPoolNums != 8 ||
+		c.BrokerExpiredTimeMills != 5000 {
+		t.Fatalf("loaded %+v", c)
+	}
+}
+
+func TestLoadShippedConfig(t *testing.T) {
+	c, err := Load("namesrv.toml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.RocketmqHome != "/usr/local/rocketmq" {
+		t.Fatalf("rocketmqHome = %q", c.RocketmqHome)
+	}
+}
+
+func TestLoadUnknownKeys(t *testing.T) {
+	_, err := Load(tempConf(t, "rocketMQHomeDir = \"/usr/local/rocketmq\"\nlistenPort = 9876\n"), nil)
+	if err == nil || !strings.Contains(err.Error(), "rocketMQHomeDir, listenPort") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestLoadLayers(t *testing.T) {
+	confPath := tempConf(t, "listenAddr = \"0.0.0.0:9876\"\nlogLevel = \"info\"\nclusterTest = false\n")
+
+	os.Setenv("ROCKETMQ_LISTEN_ADDR", "127.0.0.1:9877")
+	os.Setenv("ROCKETMQ_LOG_LEVEL", "warn")
+	os.Setenv("ROCKETMQ_TLS_ALLOWED_SUBJECTS", "broker-a, broker-b")
+	defer os.Unsetenv("ROCKETMQ_LISTEN_ADDR")
+	defer os.Unsetenv("ROCKETMQ_LOG_LEVEL")
+	defer os.Unsetenv("ROCKETMQ_TLS_ALLOWED_SUBJECTS")
+
+	fs := flag.NewFlagSet("namesrv", flag.ContinueOnError)
+	flags := BindFlags(fs)
+	if err := fs.Parse([]string{"-logLevel=error", "-clusterTest"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load(confPath, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ListenAddr != "127.0.0.1:9877" || c.LogLevel != "error" || !c.ClusterTest ||
+		len(c.TLSAllowedSubjects) != 2 || c.TLSAllowedSubjects[1] != "broker-b" {
+		t.Fatalf("loaded %+v", c)
+	}
+
+	os.Setenv("ROCKETMQ_LOG_LEVEL", "loud")
+	if _, err := Load(confPath, nil); err == nil {
+		t.Fatal("invalid environment value accepted")
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]string{
+		"listenAddr":                   "ROCKETMQ_LISTEN_ADDR",
+		"tlsCaPath":                    "ROCKETMQ_TLS_CA_PATH",
+		"printKVConfigIntervalSeconds": "ROCKETMQ_PRINT_KV_CONFIG_INTERVAL_SECONDS",
+		"rocketmqHome":                 "ROCKETMQ_HOME",
+	}
+	for key, want := range tests {
+		if got := envName(key); got != want {
+			t.Errorf("envName(%s) = %s, want %s", key, got, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	acl := func(c *Config) { c.ACLEnable, c.ACLConfigPath, c.RocketmqHome = true, "namesrv.toml", "/rocketmq" }
+	tests := map[string]func(c *Config){
+		"relative path":  func(c *Config) { c.KVConfigPath = "namesrv/kvConfig.json" },
+		"no port":        func(c *Config) { c.ListenAddr = "0.0.0.0" },
+		"bad port":       func(c *Config) { c.AdminListenAddr = "127.0.0.1:98760" },
+		"same address":   func(c *Config) { c.MetricsListenAddr = c.ListenAddr },
+		"missing cert":   func(c *Config) { c.TLSEnable, c.TLSCertPath, c.TLSKeyPath = true, "/no/cert.pem", "/no/key.pem" },
+		"no acl config":  func(c *Config) { c.ACLEnable = true },
+		"public admin":   func(c *Config) { acl(c); c.AdminListenAddr = ":9877" },
+		"no audit log":   func(c *Config) { acl(c); c.RocketmqHome = "" },
+		"no pool thread": func(c *Config) { c.BrokerThreadPoolNums = 0 },
+	}
+	for name, mutate := range tests {
+		c := Default()
+		mutate(c)
+		if err := c.Validate(); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("defaults rejected: %v", err)
+	}
+
+	c := Default()
+	acl(c)
+	c.AdminListenAddr = "127.0.0.1:9877"
+	if err := c.Validate(); err != nil {
+		t.Fatalf("loopback admin with acl rejected: %v", err)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	storePath := filepath.Join(dir, "namesrv.properties")
+	writeConf(t, storePath, "brokerExpiredTimeMills=1000\nscanNotActiveBrokerIntervalSeconds=2\n"+
+		"printKVConfigIntervalSeconds=3\nlogLevel=info\nlistenAddr=127.0.0.1:1\n")
+	confPath := filepath.Join(dir, "namesrv.toml")
+	writeConf(t, confPath, "configStorePath = \""+storePath+"\"\n"+
+		"scanNotActiveBrokerIntervalSeconds = 20\nprintKVConfigIntervalSeconds = 30\nlogLevel = \"warn\"\n")
+
+	os.Setenv("ROCKETMQ_PRINT_KV_CONFIG_INTERVAL_SECONDS", "300")
+	os.Setenv("ROCKETMQ_LOG_LEVEL", "error")
+	defer os.Unsetenv("ROCKETMQ_PRINT_KV_CONFIG_INTERVAL_SECONDS")
+	defer os.Unsetenv("ROCKETMQ_LOG_LEVEL")
+	flags := Flags{"logLevel": "debug"}
+
+	c, err := Load(confPath, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct{ got, want interface{} }{
+		"default":    {c.ShutdownTimeoutSeconds, 30},
+		"store":      {c.BrokerExpiredTimeMills, int64(1000)},
+		"file":       {c.ScanNotActiveBrokerIntervalSeconds, 20},
+		"env":        {c.PrintKVConfigIntervalSeconds, 300},
+		"flag":       {c.LogLevel, "debug"},
+		"static key": {c.ListenAddr, "0.0.0.0:9876"},
+	}
+	for layer, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s layer: got %v, want %v", layer, tt.got, tt.want)
+		}
+	}
+}
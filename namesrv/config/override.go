@@ -0,0 +1,104 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+const (
+	envPrefix = "ROCKETMQ_"
+)
+
+// Flags are the config values given on the command line, by key.
+type Flags map[string]string
+
+// BindFlags defines a flag on fs for every config key, e.g.
+// -listenAddr=0.0.0.0:9876, the returned Flags hold the ones set once fs
+// is parsed.
+func BindFlags(fs *flag.FlagSet) Flags {
+	flags := make(Flags)
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		key := keyOf(t.Field(i))
+		if key == "" {
+			continue
+		}
+		value := &flagValue{
+			flags:  flags,
+			key:    key,
+			isBool: t.Field(i).Type.Kind() == reflect.Bool,
+		}
+		fs.Var(value, key, fmt.Sprintf("override config key %s, over $%s", key, envName(key)))
+	}
+	return flags
+}
+
+type flagValue struct {
+	flags  Flags
+	key    string
+	isBool bool
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.flags[v.key]
+}
+
+func (v *flagValue) Set(s string) error {
+	v.flags[v.key] = s
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+// envName is the environment variable of key, ROCKETMQ_ and key in upper
+// snake case, e.g. ROCKETMQ_LISTEN_ADDR. Keys starting with rocketmq are not
+// prefixed again, rocketmqHome is ROCKETMQ_HOME.
+func envName(key string) string {
+	var b strings.Builder
+	runes := []rune(key)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	name := b.String()
+	if strings.HasPrefix(name, envPrefix) {
+		return name
+	}
+	return envPrefix + name
+}
+
+// override applies the environment variables and then flags to c.
+func (c *Config) override(flags Flags) error {
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		key := keyOf(v.Type().Field(i))
+		if key == "" {
+			continue
+		}
+
+		if value, ok := os.LookupEnv(envName(key)); ok {
+			if err := parseValue(v.Field(i), value); err != nil {
+				return fmt.Errorf("invalid value of $%s: %v", envName(key), err)
+			}
+		}
+		if value, ok := flags[key]; ok {
+			if err := parseValue(v.Field(i), value); err != nil {
+				return fmt.Errorf("invalid value of flag -%s: %v", key, err)
+			}
+		}
+	}
+	return nil
+}
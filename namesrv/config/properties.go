@@ -0,0 +1,263 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"reflect"
+	. "rocketmq-go/common"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrStaticKey = errors.New("can not be updated at runtime")
+
+	// Keys which only take effect on startup
+	staticKeys = map[string]bool{
+		"rocketmqHome":      true,
+		"kvConfigPath":      true,
+		"configStorePath":   true,
+		"listenAddr":        true,
+		"nativeListenAddr":  true,
+		"adminListenAddr":   true,
+		"metricsListenAddr": true,
+
+		"defaultThreadPoolNums":          true,
+		"defaultThreadPoolQueueCapacity": true,
+		"brokerThreadPoolNums":           true,
+		"brokerThreadPoolQueueCapacity":  true,
+
+		"tlsEnable":                true,
+		"tlsCertPath":              true,
+		"tlsKeyPath":               true,
+		"tlsCaPath":                true,
+		"tlsNeedClientAuth":        true,
+		"tlsAllowedSubjects":       true,
+		"tlsReloadIntervalSeconds": true,
+
+		"aclEnable":       true,
+		"aclConfigPath":   true,
+		"aclAuditLogPath": true,
+	}
+
+	updateLock sync.Mutex
+)
+
+// Snapshot returns a copy of the config taken under the lock of Update and
+// Apply. Code running while the config may change reads it through a
+// snapshot, never the fields of the config itself.
+func (c *Config) Snapshot() *Config {
+	updateLock.Lock()
+	defer updateLock.Unlock()
+
+	snapshot := *c
+	snapshot.stored = nil
+	return &snapshot
+}
+
+// Properties returns the config in java properties format, one key=value a
+// line in the order of the fields.
+func (c *Config) Properties() string {
+	updateLock.Lock()
+	defer updateLock.Unlock()
+
+	var b strings.Builder
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		key := keyOf(v.Type().Field(i))
+		if key == "" {
+			continue
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(formatValue(v.Field(i)))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Update applies properties to the config, either all of them or none when
+// one is unknown, static or has a bad value.
+func (c *Config) Update(properties map[string]string) error {
+	updateLock.Lock()
+	defer updateLock.Unlock()
+
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	v := reflect.ValueOf(c).Elem()
+	updated := reflect.New(v.Type()).Elem()
+	updated.Set(v)
+	for _, key := range keys {
+		if staticKeys[key] {
+			return fmt.Errorf("config key %s %w", key, ErrStaticKey)
+		}
+
+		field, ok := fieldByKey(updated, key)
+		if !ok {
+			return fmt.Errorf("unknown config key: %s", key)
+		}
+		if err := parseValue(field, properties[key]); err != nil {
+			return fmt.Errorf("invalid value of config key %s: %v", key, err)
+		}
+	}
+	for _, key := range keys {
+		if err := updated.Addr().Interface().(*Config).check(key); err != nil {
+			return err
+		}
+	}
+
+	if c.stored == nil {
+		c.stored = make(map[string]string)
+	}
+	// Only the keys updated, the others may be read by a snapshot
+	for _, key := range keys {
+		field, _ := fieldByKey(v, key)
+		updatedField, _ := fieldByKey(updated, key)
+		field.Set(updatedField)
+		c.stored[key] = formatValue(field)
+	}
+	return nil
+}
+
+// Persist writes the keys updated at runtime, including the ones loaded
+// from the store, to ConfigStorePath. The other keys are left to the
+// config file.
+func (c *Config) Persist() error {
+	updateLock.Lock()
+	path := c.ConfigStorePath
+	if path == "" {
+		updateLock.Unlock()
+		return nil
+	}
+	keys := make([]string, 0, len(c.stored))
+	for key := range c.stored {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(c.stored[key])
+		b.WriteByte('\n')
+	}
+	updateLock.Unlock()
+
+	return String2File([]byte(b.String()), path)
+}
+
+// LoadStore applies the properties persisted to ConfigStorePath by earlier
+// runtime updates, static keys in it are ignored. The keys applied count as
+// updated at runtime, Persist keeps them.
+func (c *Config) LoadStore() error {
+	if c.ConfigStorePath == "" {
+		return nil
+	}
+
+	data, err := File2String(c.ConfigStorePath)
+	if err != nil || data == nil {
+		return err
+	}
+
+	properties, err := ParseProperties(string(data))
+	if err != nil {
+		return err
+	}
+	for key := range staticKeys {
+		delete(properties, key)
+	}
+	return c.Update(properties)
+}
+
+// ParseProperties parses java properties text, blank lines and lines
+// starting with # or ! are skipped.
+func ParseProperties(content string) (map[string]string, error) {
+	properties := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		idx := strings.IndexAny(line, "=:")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid property line: %s", line)
+		}
+		properties[strings.TrimSpace(line[:idx])] = strings.TrimSpace(line[idx+1:])
+	}
+	return properties, scanner.Err()
+}
+
+func keyOf(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+	key := strings.Split(field.Tag.Get("toml"), ",")[0]
+	if key == "-" {
+		return ""
+	}
+	return key
+}
+
+func fieldByKey(v reflect.Value, key string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		if keyOf(v.Type().Field(i)) == key {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items[i] = formatValue(v.Index(i))
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+func parseValue(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		items := make([]string, 0)
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	c := &Config{ListenAddr: "0.0.0.0:9876"}
+
+	if err := c.Update(map[string]string{"orderMessageEnable": "true", "clusterTest": "true"}); err != nil {
+		t.Fatal(err)
+	}
+	if !c.OrderMessageEnable || !c.ClusterTest {
+		t.Fatalf("config not updated: %+v", c)
+	}
+
+	err := c.Update(map[string]string{"listenAddr": "0.0.0.0:9877", "clusterTest": "false"})
+	if !errors.Is(err, ErrStaticKey) {
+		t.Fatalf("update listenAddr err = %v", err)
+	}
+	if c.ListenAddr != "0.0.0.0:9876" || !c.ClusterTest {
+		t.Fatalf("rejected update partially applied: %+v", c)
+	}
+
+	if err := c.Update(map[string]string{"noSuchKey": "1"}); err == nil {
+		t.Fatal("unknown key accepted")
+	}
+	if err := c.Update(map[string]string{"clusterTest": "maybe"}); err == nil {
+		t.Fatal("bad bool accepted")
+	}
+}
+
+func TestPersistAndLoadStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	storePath := filepath.Join(dir, "namesrv.properties")
+	c := Default()
+	c.ConfigStorePath = storePath
+	c.ListenAddr = "0.0.0.0:9876"
+	if err := c.Update(map[string]string{"orderMessageEnable": "true"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Persist(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := Default()
+	loaded.ConfigStorePath = storePath
+	loaded.ListenAddr = "0.0.0.0:9999"
+	if err := loaded.LoadStore(); err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.OrderMessageEnable {
+		t.Fatal("persisted value not loaded")
+	}
+	if loaded.ListenAddr != "0.0.0.0:9999" {
+		t.Fatalf("static key loaded from store: %s", loaded.ListenAddr)
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	properties, err := ParseProperties("# comment\n\nclusterTest = true\nlistenAddr=0.0.0.0:9876\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if properties["clusterTest"] != "true" || properties["listenAddr"] != "0.0.0.0:9876" {
+		t.Fatalf("parsed %v", properties)
+	}
+
+	if _, err := ParseProperties("novalue"); err == nil {
+		t.Fatal("line without separator accepted")
+	}
+}
+
+func TestPersistWritesRuntimeUpdatesOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	storePath := filepath.Join(dir, "namesrv.properties")
+	writeConf(t, storePath, "clusterTest=true\n")
+	c := Default()
+	c.ConfigStorePath = storePath
+	if err := c.LoadStore(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(map[string]string{"brokerExpiredTimeMills": "120000"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Persist(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(storePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "brokerExpiredTimeMills=120000\nclusterTest=true\n"; string(data) != want {
+		t.Fatalf("store = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"reflect"
+)
+
+var (
+	// Static keys which a reload of the config file still applies, the
+	// running server switches to the new files right away
+	reloadableStaticKeys = map[string]bool{
+		"tlsCertPath": true,
+		"tlsKeyPath":  true,
+		"tlsCaPath":   true,
+	}
+)
+
+// Change is a config key whose value differs between two configs, Static
+// ones take effect only after a restart.
+type Change struct {
+	Key    string
+	Old    string
+	New    string
+	Static bool
+}
+
+// Apply takes the keys changed in loaded, a valid config e.g. from Load,
+// but the static ones. It returns every change, static ones included.
+func (c *Config) Apply(loaded *Config) []Change {
+	updateLock.Lock()
+	defer updateLock.Unlock()
+
+	changes := make([]Change, 0)
+	v := reflect.ValueOf(c).Elem()
+	loadedV := reflect.ValueOf(loaded).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		key := keyOf(v.Type().Field(i))
+		if key == "" {
+			continue
+		}
+		before, after := formatValue(v.Field(i)), formatValue(loadedV.Field(i))
+		if before == after {
+			continue
+		}
+
+		static := staticKeys[key] && !reloadableStaticKeys[key]
+		changes = append(changes, Change{Key: key, Old: before, New: after, Static: static})
+		if !static {
+			v.Field(i).Set(loadedV.Field(i))
+		}
+	}
+	return changes
+}
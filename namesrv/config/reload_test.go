@@ -0,0 +1,94 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeConf(t *testing.T, path string, content string) {
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	confPath := filepath.Join(dir, "namesrv.toml")
+	writeConf(t, confPath, "listenAddr = \"0.0.0.0:9876\"\nlogLevel = \"info\"\ntlsCertPath = \"old.pem\"\n")
+	c, err := Load(confPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeConf(t, confPath, "listenAddr = \"0.0.0.0:9877\"\nlogLevel = \"warn\"\ntlsCertPath = \"new.pem\"\n"+
+		"brokerExpiredTimeMills = 120000\n")
+	loaded, err := Load(confPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := c.Apply(loaded)
+	want := []Change{
+		{Key: "listenAddr", Old: "0.0.0.0:9876", New: "0.0.0.0:9877", Static: true},
+		{Key: "logLevel", Old: "info", New: "warn"},
+		{Key: "brokerExpiredTimeMills", Old: "5000", New: "120000"},
+		{Key: "tlsCertPath", Old: "old.pem", New: "new.pem"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes = %+v, want %+v", changes, want)
+	}
+	if c.ListenAddr != "0.0.0.0:9876" || c.LogLevel != "warn" || c.BrokerExpiredTimeMills != 120000 ||
+		c.TLSCertPath != "new.pem" {
+		t.Fatalf("config after reload: %+v", c)
+	}
+
+	if changes := c.Apply(loaded); len(changes) != 1 || changes[0].Key != "listenAddr" {
+		t.Fatalf("changes of applying again = %+v, want the static listenAddr only", changes)
+	}
+}
+
+func TestApplyFileEditAfterUpdate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	confPath := filepath.Join(dir, "namesrv.toml")
+	storeLine := "configStorePath = \"" + filepath.Join(dir, "namesrv.properties") + "\"\n"
+	writeConf(t, confPath, storeLine+"logLevel = \"info\"\n")
+	c, err := Load(confPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(map[string]string{"logLevel": "warn", "brokerExpiredTimeMills": "120000"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Persist(); err != nil {
+		t.Fatal(err)
+	}
+
+	writeConf(t, confPath, storeLine+"logLevel = \"error\"\nscanNotActiveBrokerIntervalSeconds = 30\n")
+	loaded, err := Load(confPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := c.Apply(loaded)
+	want := []Change{
+		{Key: "logLevel", Old: "warn", New: "error"},
+		{Key: "scanNotActiveBrokerIntervalSeconds", Old: "10", New: "30"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes = %+v, want %+v", changes, want)
+	}
+	if c.LogLevel != "error" || c.ScanNotActiveBrokerIntervalSeconds != 30 || c.BrokerExpiredTimeMills != 120000 {
+		t.Fatalf("config after reload: %+v", c)
+	}
+}
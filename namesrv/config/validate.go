@@ -0,0 +1,204 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+)
+
+var (
+	// checks validate the value of a key, other keys take any value of
+	// their type
+	checks = map[string]func(c *Config) error{
+		"rocketmqHome": func(c *Config) error {
+			return absolutePath(c.RocketmqHome)
+		},
+		"kvConfigPath": func(c *Config) error {
+			return absolutePath(c.KVConfigPath)
+		},
+		"configStorePath": func(c *Config) error {
+			return absolutePath(c.ConfigStorePath)
+		},
+		"aclAuditLogPath": func(c *Config) error {
+			return absolutePath(c.ACLAuditLogPath)
+		},
+		"listenAddr": func(c *Config) error {
+			if c.ListenAddr == "" {
+				return errors.New("must not be empty")
+			}
+			return listenAddr(c.ListenAddr)
+		},
+		"nativeListenAddr": func(c *Config) error {
+			return listenAddr(c.NativeListenAddr)
+		},
+		"adminListenAddr": func(c *Config) error {
+			return listenAddr(c.AdminListenAddr)
+		},
+		"metricsListenAddr": func(c *Config) error {
+			if err := listenAddr(c.MetricsListenAddr); err != nil {
+				return err
+			}
+			return distinctAddrs(c)
+		},
+		"shutdownTimeoutSeconds": func(c *Config) error {
+			return positive(int64(c.ShutdownTimeoutSeconds))
+		},
+		"logLevel": func(c *Config) error {
+			switch c.LogLevel {
+			case "debug", "info", "warn", "error":
+				return nil
+			}
+			return fmt.Errorf("unknown log level %q", c.LogLevel)
+		},
+		"brokerExpiredTimeMills": func(c *Config) error {
+			return positive(c.BrokerExpiredTimeMills)
+		},
+		"scanNotActiveBrokerIntervalSeconds": func(c *Config) error {
+			return positive(int64(c.ScanNotActiveBrokerIntervalSeconds))
+		},
+		"printKVConfigIntervalSeconds": func(c *Config) error {
+			return positive(int64(c.PrintKVConfigIntervalSeconds))
+		},
+		"defaultThreadPoolNums": func(c *Config) error {
+			return positive(int64(c.DefaultThreadPoolNums))
+		},
+		"defaultThreadPoolQueueCapacity": func(c *Config) error {
+			return positive(int64(c.DefaultThreadPoolQueueCapacity))
+		},
+		"brokerThreadPoolNums": func(c *Config) error {
+			return positive(int64(c.BrokerThreadPoolNums))
+		},
+		"brokerThreadPoolQueueCapacity": func(c *Config) error {
+			return positive(int64(c.BrokerThreadPoolQueueCapacity))
+		},
+		"tlsEnable": func(c *Config) error {
+			if !c.TLSEnable {
+				return nil
+			}
+			if c.TLSCertPath == "" || c.TLSKeyPath == "" {
+				return errors.New("tlsCertPath and tlsKeyPath are required")
+			}
+			return existingFiles(c.TLSCertPath, c.TLSKeyPath, c.TLSCAPath)
+		},
+		"aclEnable": func(c *Config) error {
+			if !c.ACLEnable {
+				return nil
+			}
+			if c.ACLConfigPath == "" {
+				return errors.New("aclConfigPath is required")
+			}
+			if c.ACLAuditLogPath == "" && c.RocketmqHome == "" {
+				return errors.New("aclAuditLogPath or rocketmqHome is required")
+			}
+			// The admin api has no authentication, keep it off the network
+			if c.AdminListenAddr != "" && !loopbackAddr(c.AdminListenAddr) {
+				return fmt.Errorf("adminListenAddr %s must be a loopback address with acl enabled", c.AdminListenAddr)
+			}
+			return existingFiles(c.ACLConfigPath)
+		},
+	}
+)
+
+// Validate checks the values the name server can not run with.
+func (c *Config) Validate() error {
+	t := reflect.TypeOf(c).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		if err := c.check(keyOf(t.Field(i))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) check(key string) error {
+	check, ok := checks[key]
+	if !ok {
+		return nil
+	}
+	if err := check(c); err != nil {
+		return fmt.Errorf("invalid value of config key %s: %v", key, err)
+	}
+	return nil
+}
+
+func positive(v int64) error {
+	if v <= 0 {
+		return fmt.Errorf("must be positive, got %d", v)
+	}
+	return nil
+}
+
+// absolutePath allows empty paths, which disable what they are for.
+func absolutePath(path string) error {
+	if path != "" && !filepath.IsAbs(path) {
+		return fmt.Errorf("%s is not an absolute path", path)
+	}
+	return nil
+}
+
+// listenAddr allows empty addresses, which disable their listener.
+func listenAddr(addr string) error {
+	if addr == "" {
+		return nil
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 0 || p > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	return nil
+}
+
+// loopbackAddr tells whether addr only takes local connections.
+func loopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// distinctAddrs checks the listeners do not take the same address, port 0
+// picks a free port for each of them.
+func distinctAddrs(c *Config) error {
+	seen := make(map[string]bool)
+	for _, addr := range []string{c.ListenAddr, c.NativeListenAddr, c.AdminListenAddr, c.MetricsListenAddr} {
+		if addr == "" {
+			continue
+		}
+		if _, port, _ := net.SplitHostPort(addr); port == "0" {
+			continue
+		}
+		if seen[addr] {
+			return fmt.Errorf("%s is taken by two listeners", addr)
+		}
+		seen[addr] = true
+	}
+	return nil
+}
+
+func existingFiles(paths ...string) error {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory", path)
+		}
+	}
+	return nil
+}
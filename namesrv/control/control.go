@@ -1,13 +1,18 @@
 package control
 
 import (
-	"os"
+	"context"
+	"go.uber.org/zap"
+	. "rocketmq-go/common/clock"
+	. "rocketmq-go/common/proto/route"
 	. "rocketmq-go/logging"
 	. "rocketmq-go/namesrv/config"
 	. "rocketmq-go/namesrv/kvconfig"
+	. "rocketmq-go/namesrv/notifier"
 	. "rocketmq-go/namesrv/routeinfo"
 	. "rocketmq-go/namesrv/scheduler"
 	. "rocketmq-go/remote"
+	"sync"
 	"time"
 )
 
@@ -18,36 +23,211 @@ const (
 
 type Control struct {
 	RemoteSrv Service
+	// NativeSrv serves java remoting clients, nil when disabled
+	NativeSrv Service
+	// AdminSrv serves the HTTP admin API, nil when disabled
+	AdminSrv Service
+	// MetricsSrv serves Metrics for prometheus, nil when disabled
+	MetricsSrv Service
+	Metrics *Metrics
 	RouteInfo *RouteInfo
 	KVConfig *KVConfig
+	// NameSrvConf changes at runtime, read it through Snapshot
 	NameSrvConf *Config
+	Notifier *Notifier
+	BrokerHousekeepingService *BrokerHousekeepingService
 
 	scheduler *Scheduler
-	stopChan chan os.Signal
+
+	applyLock sync.Mutex
+	periods map[int]time.Duration
+	reloadListeners []func(*Config)
+
+	// Apart from applyLock, applying the config waits for the scan
+	scanLock sync.Mutex
+	scanListeners []func(expired int)
 }
 
-func NewControl(confPath string, stopChan chan os.Signal) *Control {
+// NewControl creates the control of a name server running with conf, which
+// should be valid, e.g. returned by config.Load.
+func NewControl(conf *Config) *Control {
 	var control Control
 	control.RouteInfo = NewRouteInfo()
-	control.KVConfig = NewKVConfig()
-	control.NameSrvConf = NewConfig(confPath)
+	control.NameSrvConf = conf
+	control.KVConfig = NewKVConfig(control.NameSrvConf.KVConfigPath)
+	control.Notifier = NewNotifier(control.PickupTopicRouteData)
+	control.RouteInfo.SetTopicRouteListener(control.Notifier.OnTopicRouteChanged)
+	control.BrokerHousekeepingService = NewBrokerHousekeepingService(control.RouteInfo)
+	control.Metrics = newMetrics(&control)
 	control.scheduler = NewScheduler()
-	control.stopChan = stopChan
+	control.periods = make(map[int]time.Duration)
 
 	return &control
 }
 
-func (c *Control) Start() {
-	c.RemoteSrv.Start()
-	c.scheduler.Add(brokerActiveCheck, 10 * time.Second, c.RouteInfo.ScanNotActiveBroker)
-	c.scheduler.Add(kvConfigPrint, 10 * time.Minute, c.KVConfig.PrintAllPeriodically)
+// SetClock changes the clock of the route info and the scheduled tasks, it
+// must be called before Start.
+func (c *Control) SetClock(clock Clock) {
+	c.RouteInfo.SetClock(clock)
+	c.scheduler.SetClock(clock)
+}
+
+// Start loads the KV config and starts the services, then the scheduled
+// tasks. The services started are stopped when one fails to start.
+func (c *Control) Start() error {
+	if err := c.KVConfig.Load(); err != nil {
+		return err
+	}
+
+	var started []Service
+	for _, srv := range []Service{c.RemoteSrv, c.NativeSrv, c.AdminSrv, c.MetricsSrv} {
+		if srv == nil {
+			continue
+		}
+		if err := srv.Start(); err != nil {
+			for i := len(started) - 1; i >= 0; i-- {
+				started[i].Stop()
+			}
+			return err
+		}
+		started = append(started, srv)
+	}
+	c.applyConfig()
 	c.scheduler.Start()
+	return nil
+}
+
+// OnReload calls listener with the config after every reload, e.g. to
+// reload the files it names.
+func (c *Control) OnReload(listener func(*Config)) {
+	c.applyLock.Lock()
+	defer c.applyLock.Unlock()
+
+	c.reloadListeners = append(c.reloadListeners, listener)
+}
+
+// OnScanNotActiveBroker calls listener with the number of brokers removed
+// after every scheduled scan for not active brokers.
+func (c *Control) OnScanNotActiveBroker(listener func(expired int)) {
+	c.scanLock.Lock()
+	defer c.scanLock.Unlock()
+
+	c.scanListeners = append(c.scanListeners, listener)
 }
 
-func (c *Control) Stop() {
-	sig := <- c.stopChan
-	Log.Sugar().Debugf("Signal: %v", sig)
-	c.RemoteSrv.Stop()
+// Reload applies the settings of conf which can change while running,
+// conf should be valid, e.g. returned by config.Load.
+func (c *Control) Reload(conf *Config) {
+	changes := c.NameSrvConf.Apply(conf)
+
+	for _, change := range changes {
+		if change.Static {
+			Log.Warn("Config changed, it takes effect after a restart",
+				zap.String("key", change.Key),
+				zap.String("old", change.Old),
+				zap.String("new", change.New))
+			continue
+		}
+		Log.Info("Config changed",
+			zap.String("key", change.Key),
+			zap.String("old", change.Old),
+			zap.String("new", change.New))
+	}
+	c.applyConfig()
+
+	c.applyLock.Lock()
+	listeners := c.reloadListeners
+	c.applyLock.Unlock()
+	conf = c.NameSrvConf.Snapshot()
+	for _, listener := range listeners {
+		listener(conf)
+	}
+	Log.Info("Config reloaded", zap.Int("changes", len(changes)))
+}
+
+// UpdateConfig updates the config at runtime, see Config.Update, and
+// applies the settings changed.
+func (c *Control) UpdateConfig(properties map[string]string) error {
+	if err := c.NameSrvConf.Update(properties); err != nil {
+		return err
+	}
+	c.applyConfig()
+	return nil
+}
+
+// applyConfig puts the settings which can change while running into
+// effect.
+func (c *Control) applyConfig() {
+	c.applyLock.Lock()
+	defer c.applyLock.Unlock()
+
+	conf := c.NameSrvConf.Snapshot()
+	if err := SetLevel(conf.LogLevel); err != nil {
+		Log.Error("Set log level failed", zap.Error(err))
+	}
+	c.RouteInfo.SetBrokerExpiredTime(conf.BrokerExpiredTimeMills)
+	c.schedule(brokerActiveCheck, time.Duration(conf.ScanNotActiveBrokerIntervalSeconds) * time.Second,
+		c.Metrics.timed("scanNotActiveBroker", c.scanNotActiveBroker))
+	c.schedule(kvConfigPrint, time.Duration(conf.PrintKVConfigIntervalSeconds) * time.Second,
+		c.Metrics.timed("printAllKVConfig", c.KVConfig.PrintAllPeriodically))
+}
+
+// schedule runs event every period, replacing the timer of id when its
+// period changed.
+func (c *Control) schedule(id int, period time.Duration, event Event) {
+	if c.periods[id] == period {
+		return
+	}
+	c.scheduler.Del(id)
+	c.scheduler.Add(id, period, event)
+	c.periods[id] = period
+}
+
+// Shutdown stops the listeners letting their requests in flight finish,
+// those not done when ctx is done are stopped right away. Then it waits for
+// the scheduled tasks running and persists the KV config. It returns the
+// first error of the services.
+func (c *Control) Shutdown(ctx context.Context) error {
+	Log.Info("Shutting down the name server")
+	var firstErr error
+	// Admin first so nothing changes while draining, the native server
+	// before the remote one whose executors it shares
+	for _, srv := range []Service{c.AdminSrv, c.NativeSrv, c.RemoteSrv, c.MetricsSrv} {
+		if srv == nil {
+			continue
+		}
+		if err := srv.Shutdown(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
 	c.scheduler.Stop()
+	c.KVConfig.Persist()
+	Log.Info("Name server shutdown")
+	return firstErr
+}
+
+func (c *Control) scanNotActiveBroker() {
+	expired := c.RouteInfo.ScanNotActiveBroker()
+	c.Metrics.expiredBrokers.Add(float64(expired))
+
+	c.scanLock.Lock()
+	listeners := c.scanListeners
+	c.scanLock.Unlock()
+	for _, listener := range listeners {
+		listener(expired)
+	}
 }
 
+// PickupTopicRouteData returns the route of the topic with the order topic
+// config filled in when order message is enabled.
+func (c *Control) PickupTopicRouteData(topic string) *TopicRouteData {
+	topicRouteData := c.RouteInfo.PickupTopicRouteData(topic)
+	if topicRouteData == nil {
+		return nil
+	}
+
+	if c.NameSrvConf.Snapshot().OrderMessageEnable {
+		topicRouteData.OrderTopicConf = c.KVConfig.GetKVConfig(NamespaceOrderTopicConfig, topic)
+	}
+	return topicRouteData
+}
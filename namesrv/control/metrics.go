@@ -0,0 +1,107 @@
+package control
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	pb "rocketmq-go/common/proto"
+	. "rocketmq-go/remote"
+	"time"
+)
+
+// Metrics are the name server metrics, served at /metrics of the metrics
+// listener.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	requests       *prometheus.CounterVec
+	requestLatency *prometheus.HistogramVec
+	expiredBrokers prometheus.Counter
+	taskDuration   *prometheus.HistogramVec
+}
+
+func newMetrics(c *Control) *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rocketmq_namesrv_requests_total",
+			Help: "Requests processed, by request code and response code.",
+		}, []string{"code", "response_code"}),
+		requestLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "rocketmq_namesrv_request_duration_seconds",
+			Help: "Time taken to process a request, by request code.",
+		}, []string{"code"}),
+		expiredBrokers: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "rocketmq_namesrv_expired_brokers_total",
+			Help: "Brokers removed by the scan for not active brokers.",
+		}),
+		taskDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "rocketmq_namesrv_scheduler_task_duration_seconds",
+			Help: "Time taken by a run of a scheduled task, by task.",
+		}, []string{"task"}),
+	}
+	m.Registry.MustRegister(m.requests, m.requestLatency, m.expiredBrokers, m.taskDuration)
+
+	m.gauge("rocketmq_namesrv_brokers", "Live brokers.", func() float64 {
+		brokers, _, _ := c.RouteInfo.Counts()
+		return float64(brokers)
+	})
+	m.gauge("rocketmq_namesrv_clusters", "Clusters with brokers.", func() float64 {
+		_, clusters, _ := c.RouteInfo.Counts()
+		return float64(clusters)
+	})
+	m.gauge("rocketmq_namesrv_topics", "Topics with a route.", func() float64 {
+		_, _, topics := c.RouteInfo.Counts()
+		return float64(topics)
+	})
+	m.gauge("rocketmq_namesrv_kv_configs", "KV config entries.", func() float64 {
+		return float64(c.KVConfig.Size())
+	})
+	return m
+}
+
+// gauge registers a gauge whose value is read from f on every scrape, f
+// must be safe to call concurrently.
+func (m *Metrics) gauge(name string, help string, f func() float64) {
+	m.Registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, f))
+}
+
+// Interceptor counts every request by its response code and observes its
+// latency. It should be the outermost, to see the responses of the others.
+func (m *Metrics) Interceptor() Interceptor {
+	return Latency(func(request *pb.RemoteCommand, response *pb.RemoteCommand, cost time.Duration) {
+		// Unknown codes share a label, or any client could add series
+		code := "UNKNOWN"
+		if _, ok := pb.RequestCode_name[request.Code]; ok {
+			code = pb.RequestCode(request.Code).String()
+		}
+		responseCode := "NONE"
+		if response != nil {
+			responseCode = pb.ResponseCode(response.Code).String()
+		}
+
+		m.requests.WithLabelValues(code, responseCode).Inc()
+		m.requestLatency.WithLabelValues(code).Observe(cost.Seconds())
+	})
+}
+
+// ObserveConnections exports the number of connections returned by active
+// under the transport label, usually the ActiveConnections of the gRPC or
+// the native remoting server. Each transport is observed once.
+func (m *Metrics) ObserveConnections(transport string, active func() int64) {
+	m.Registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "rocketmq_namesrv_connections",
+		Help:        "Open connections, by transport.",
+		ConstLabels: prometheus.Labels{"transport": transport},
+	}, func() float64 {
+		return float64(active())
+	}))
+}
+
+// timed returns task observing the time each run of it takes.
+func (m *Metrics) timed(name string, task func()) func() {
+	duration := m.taskDuration.WithLabelValues(name)
+	return func() {
+		start := time.Now()
+		task()
+		duration.Observe(time.Since(start).Seconds())
+	}
+}
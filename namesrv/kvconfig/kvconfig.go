@@ -3,31 +3,120 @@ package kvconfig
 import (
 	"encoding/json"
 	"go.uber.org/zap"
+	. "rocketmq-go/common"
 	common "rocketmq-go/common/proto/route"
 	. "rocketmq-go/logging"
 	"sync"
 )
 
+const (
+	NamespaceOrderTopicConfig = "ORDER_TOPIC_CONFIG"
+)
+
 type KVConfig struct {
 	rw sync.RWMutex
 	configTable map[string]map[string]string
+	kvConfigPath string
 }
 
-func NewKVConfig() *KVConfig {
-	var rw sync.RWMutex
-	m := make(map[string]map[string]string)
+// kvConfigSerializeWrapper is the layout of kvConfig.json, the same as the
+// java name server's
+type kvConfigSerializeWrapper struct {
+	ConfigTable map[string]map[string]string `json:"configTable"`
+}
+
+// NewKVConfig creates a KVConfig persisted to kvConfigPath, an empty path
+// keeps it in memory only.
+func NewKVConfig(kvConfigPath string) *KVConfig {
 	return &KVConfig{
-		rw,
-		m,
+		configTable: make(map[string]map[string]string),
+		kvConfigPath: kvConfigPath,
+	}
+}
+
+func (k *KVConfig) Load() error {
+	if k.kvConfigPath == "" {
+		return nil
+	}
+
+	data, err := File2String(k.kvConfigPath)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		Log.Info("load KV config, file not exist", zap.String("path", k.kvConfigPath))
+		return nil
 	}
+
+	wrapper := kvConfigSerializeWrapper{}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return err
+	}
+
+	k.rw.Lock()
+	defer k.rw.Unlock()
+
+	for namespace, kvTable := range wrapper.ConfigTable {
+		k.configTable[namespace] = kvTable
+	}
+	Log.Info("load KV config OK", zap.String("path", k.kvConfigPath))
+	return nil
 }
 
 func (k *KVConfig) PrintAllPeriodically() {
+	k.rw.RLock()
+	defer k.rw.RUnlock()
+
+	Log.Info("--------------------------------------------------------")
+	Log.Info("configTable", zap.Int("size", len(k.configTable)))
+	for namespace, kvTable := range k.configTable {
+		for key, value := range kvTable {
+			Log.Info("configTable",
+				zap.String("namespace", namespace),
+				zap.String("key", key),
+				zap.String("value", value))
+		}
+	}
+}
 
+// Size returns the number of KV config entries of every namespace.
+func (k *KVConfig) Size() int {
+	k.rw.RLock()
+	defer k.rw.RUnlock()
+
+	size := 0
+	for _, kvTable := range k.configTable {
+		size += len(kvTable)
+	}
+	return size
 }
 
+// Persist writes the KV config to kvConfigPath, errors are logged.
+func (k *KVConfig) Persist() {
+	k.rw.RLock()
+	defer k.rw.RUnlock()
+
+	k.persist()
+}
+
+// persist must be called with the lock held
 func (k *KVConfig) persist() {
+	if k.kvConfigPath == "" {
+		return
+	}
+
+	wrapper := kvConfigSerializeWrapper{ConfigTable: k.configTable}
+	data, err := json.Marshal(wrapper)
+	if err != nil {
+		Log.Error("persist KV config failed", zap.Error(err))
+		return
+	}
 
+	if err := String2File(data, k.kvConfigPath); err != nil {
+		Log.Error("persist KV config failed",
+			zap.String("path", k.kvConfigPath),
+			zap.Error(err))
+	}
 }
 
 func (k *KVConfig) PutKVConfig(namespace string, key string, value string) {
@@ -94,8 +183,7 @@ func (k *KVConfig) GetKVListByNamespace(namespace string) []byte {
 	kvTable, ok := k.configTable[namespace]
 	if ok {
 		table := common.KVTable{Table: kvTable}
-		data, _ := json.Marshal(table)
-		return data
+		return table.Encode()
 	}
 	return nil
 }
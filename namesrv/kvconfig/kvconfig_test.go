@@ -0,0 +1,69 @@
+package kvconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPersistAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kvconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "namesrv", "kvConfig.json")
+
+	k := NewKVConfig(path)
+	k.PutKVConfig(NamespaceOrderTopicConfig, "TopicA", "broker-a:4;broker-b:4")
+	k.PutKVConfig("custom", "key", "value")
+	k.DeleteKVConfig("custom", "key")
+
+	if _, err := os.Stat(path + ".bak"); err != nil {
+		t.Fatalf("no backup written: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("temp file left behind: %v", err)
+	}
+
+	loaded := NewKVConfig(path)
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if v := loaded.GetKVConfig(NamespaceOrderTopicConfig, "TopicA"); v != "broker-a:4;broker-b:4" {
+		t.Fatalf("loaded value = %q", v)
+	}
+	if v := loaded.GetKVConfig("custom", "key"); v != "" {
+		t.Fatalf("deleted key loaded with value %q", v)
+	}
+}
+
+func TestLoadJavaKVConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kvconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kvConfig.json")
+
+	content := `{"configTable":{"ORDER_TOPIC_CONFIG":{"TopicOrder":"broker-a:8"}}}`
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	k := NewKVConfig(path)
+	if err := k.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if v := k.GetKVConfig(NamespaceOrderTopicConfig, "TopicOrder"); v != "broker-a:8" {
+		t.Fatalf("loaded value = %q", v)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	k := NewKVConfig(filepath.Join(os.TempDir(), "not-exist", "kvConfig.json"))
+	if err := k.Load(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,156 @@
+package namesrvtest
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"rocketmq-go/common"
+	"rocketmq-go/common/perm"
+	pb "rocketmq-go/common/proto"
+	route "rocketmq-go/common/proto/route"
+	"rocketmq-go/remote"
+	"time"
+)
+
+const (
+	// QueueNums are the read and write queues of the topics of a Broker.
+	QueueNums = 4
+
+	requestTimeout = 3 * time.Second
+)
+
+// Broker is a fake broker with its topics.
+type Broker struct {
+	Cluster string
+	Name    string
+	Addr    string
+	// ID is 0 for a master, a slave otherwise.
+	ID     int64
+	Topics []string
+}
+
+func (b Broker) body() *pb.RegisterBrokerBody {
+	table := make(map[string]*pb.TopicConfig)
+	for _, topic := range b.Topics {
+		table[topic] = &pb.TopicConfig{
+			TopicName:      topic,
+			ReadQueueNums:  QueueNums,
+			WriteQueueNums: QueueNums,
+			Perm:           perm.PermRead | perm.PermWrite,
+		}
+	}
+	return &pb.RegisterBrokerBody{
+		TopicConfigSerializeWrapper: &pb.TopicConfigSerializeWrapper{
+			TopicConfigTable: table,
+			DataVersion:      &pb.DataVersion{Timestamp: time.Now().UnixNano() / 1e6},
+		},
+	}
+}
+
+// ResponseError is a response whose code is not SUCCESS.
+type ResponseError struct {
+	Code   pb.ResponseCode
+	Remark string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("response code %v: %s", e.Code, e.Remark)
+}
+
+// Client is a client of a Server with typed calls, the calls return a
+// *ResponseError for the responses which are not SUCCESS.
+type Client struct {
+	*remote.Client
+}
+
+// Invoke sends a request of code with header and body, either may be nil.
+func (c *Client) Invoke(code pb.RequestCode, header proto.Message, body proto.Message) (*pb.RemoteCommand, error) {
+	request := &pb.RemoteCommand{Code: int32(code)}
+	if header != nil {
+		request.Header = common.Serializable(header)
+	}
+	if body != nil {
+		request.Body = common.Serializable(body)
+	}
+
+	response, err := c.InvokeSync(context.Background(), request, requestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if response.Code != int32(pb.ResponseCode_SUCCESS) {
+		return response, &ResponseError{Code: pb.ResponseCode(response.Code), Remark: response.Remark}
+	}
+	return response, nil
+}
+
+// RegisterBroker registers b with its topics.
+func (c *Client) RegisterBroker(b Broker) (*pb.RegisterBrokerResponseHeader, error) {
+	header := &pb.RegisterBrokerRequestHeader{
+		ClusterName: b.Cluster,
+		BrokerName:  b.Name,
+		BrokerAddr:  b.Addr,
+		BrokerId:    b.ID,
+	}
+	response, err := c.Invoke(pb.RequestCode_REGISTER_BROKER, header, b.body())
+	if err != nil {
+		return nil, err
+	}
+	respHeader := &pb.RegisterBrokerResponseHeader{}
+	if err := common.Deserializable(response.Header, respHeader, false); err != nil {
+		return nil, err
+	}
+	return respHeader, nil
+}
+
+// UnRegisterBroker removes b.
+func (c *Client) UnRegisterBroker(b Broker) error {
+	header := &pb.UnRegisterBrokerHeader{
+		ClusterName: b.Cluster,
+		BrokerName:  b.Name,
+		BrokerAddr:  b.Addr,
+		BrokerId:    b.ID,
+	}
+	_, err := c.Invoke(pb.RequestCode_UNREGISTER_BROKER, header, nil)
+	return err
+}
+
+// GetRouteInfo returns the route of topic, a topic without one gets
+// TOPIC_NOT_EXIST.
+func (c *Client) GetRouteInfo(topic string) (*route.TopicRouteData, error) {
+	response, err := c.Invoke(pb.RequestCode_GET_ROUTEINFO_BY_TOPIC, &pb.GetRouteInfoRequestHeader{Topic: topic}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return route.DecodeTopicRouteData(response.Body)
+}
+
+// GetClusterInfo returns the brokers of every cluster.
+func (c *Client) GetClusterInfo() (*route.ClusterInfo, error) {
+	response, err := c.Invoke(pb.RequestCode_GET_BROKER_CLUSTER_INFO, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return route.DecodeClusterInfo(response.Body)
+}
+
+// PutKVConfig sets key of namespace to value.
+func (c *Client) PutKVConfig(namespace string, key string, value string) error {
+	header := &pb.PutKVConfigRequestHeader{Namespace: namespace, Key: key, Value: value}
+	_, err := c.Invoke(pb.RequestCode_PUT_KV_CONFIG, header, nil)
+	return err
+}
+
+// GetKVConfig returns the value of key of namespace, a key not set gets
+// QUERY_NOT_FOUND.
+func (c *Client) GetKVConfig(namespace string, key string) (string, error) {
+	header := &pb.GetKVConfigRequestHeader{Namespace: namespace, Key: key}
+	response, err := c.Invoke(pb.RequestCode_GET_KV_CONFIG, header, nil)
+	if err != nil {
+		return "", err
+	}
+	respHeader := &pb.GetKVConfigResponseHeader{}
+	if err := common.Deserializable(response.Header, respHeader, false); err != nil {
+		return "", err
+	}
+	return respHeader.Value, nil
+}
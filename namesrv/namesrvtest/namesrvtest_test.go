@@ -0,0 +1,163 @@
+package namesrvtest
+
+import (
+	"github.com/prometheus/common/expfmt"
+	pb "rocketmq-go/common/proto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func responseCode(err error) pb.ResponseCode {
+	if e, ok := err.(*ResponseError); ok {
+		return e.Code
+	}
+	return -1
+}
+
+func TestRouteOfRegisteredBrokers(t *testing.T) {
+	t.Parallel()
+	s := NewServer(t)
+	s.RegisterBroker(t, Broker{Cluster: "c", Name: "broker-a", Addr: "10.0.0.1:10911", Topics: []string{"TopicA"}})
+	s.RegisterBroker(t, Broker{Cluster: "c", Name: "broker-b", Addr: "10.0.0.2:10911", Topics: []string{"TopicA", "TopicB"}})
+
+	c := s.NewClient(t)
+	routeData, err := c.GetRouteInfo("TopicA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routeData.BrokerDatas) != 2 || len(routeData.QueueDatas) != 2 {
+		t.Fatalf("route of TopicA = %+v", routeData)
+	}
+	for _, queueData := range routeData.QueueDatas {
+		if queueData.ReadQueueNums != QueueNums || queueData.WriteQueueNums != QueueNums {
+			t.Fatalf("queue data = %+v", queueData)
+		}
+	}
+
+	if _, err := c.GetRouteInfo("TopicC"); responseCode(err) != pb.ResponseCode_TOPIC_NOT_EXIST {
+		t.Fatalf("route of unknown topic: %v", err)
+	}
+
+	clusterInfo, err := c.GetClusterInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusterInfo.ClusterAddrTable["c"]) != 2 {
+		t.Fatalf("cluster info = %+v", clusterInfo)
+	}
+}
+
+func TestBrokerDroppedWithItsChannel(t *testing.T) {
+	t.Parallel()
+	s := NewServer(t)
+	a := Broker{Cluster: "c", Name: "broker-a", Addr: "10.0.0.1:10911", Topics: []string{"TopicA"}}
+	b := Broker{Cluster: "c", Name: "broker-b", Addr: "10.0.0.2:10911", Topics: []string{"TopicB"}}
+	brokerA := s.RegisterBroker(t, a)
+	s.RegisterBroker(t, b)
+
+	notified := s.Subscribe(t, "TopicA")
+
+	brokerA.Stop()
+	select {
+	case <-notified:
+	case <-time.After(waitTimeout):
+		t.Fatal("no route change of TopicA after the channel of broker-a closed")
+	}
+	c := s.NewClient(t)
+	if _, err := c.GetRouteInfo("TopicA"); responseCode(err) != pb.ResponseCode_TOPIC_NOT_EXIST {
+		t.Fatalf("route of broker-a kept after its channel closed: %v", err)
+	}
+	if _, err := c.GetRouteInfo("TopicB"); err != nil {
+		t.Fatalf("route of broker-b: %v", err)
+	}
+
+	if err := c.UnRegisterBroker(b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetRouteInfo("TopicB"); responseCode(err) != pb.ResponseCode_TOPIC_NOT_EXIST {
+		t.Fatalf("route of unregistered broker-b: %v", err)
+	}
+}
+
+func TestKVConfig(t *testing.T) {
+	t.Parallel()
+	c := NewServer(t).NewClient(t)
+
+	if err := c.PutKVConfig("ns", "k", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := c.GetKVConfig("ns", "k"); err != nil || value != "v" {
+		t.Fatalf("get kv config = %q, %v", value, err)
+	}
+	if _, err := c.GetKVConfig("ns", "missing"); responseCode(err) != pb.ResponseCode_QUERY_NOT_FOUND {
+		t.Fatalf("get missing kv config: %v", err)
+	}
+}
+
+func TestBrokerExpiresWithoutUpdates(t *testing.T) {
+	t.Parallel()
+	conf := Config(t)
+	conf.BrokerExpiredTimeMills = 30000
+	s := NewServerWithConfig(t, conf)
+	a := Broker{Cluster: "c", Name: "broker-a", Addr: "10.0.0.1:10911", Topics: []string{"TopicA"}}
+	brokerA := s.RegisterBroker(t, a)
+
+	s.Advance(20 * time.Second)
+	if _, err := brokerA.RegisterBroker(a); err != nil {
+		t.Fatal(err)
+	}
+	s.Advance(30 * time.Second)
+	if expired := s.ScanNotActiveBroker(); expired != 0 {
+		t.Fatalf("expired %d brokers within the expired time of the last register", expired)
+	}
+	s.Advance(time.Millisecond)
+	if expired := s.ScanNotActiveBroker(); expired != 1 {
+		t.Fatalf("expired %d brokers, want broker-a", expired)
+	}
+	if _, err := s.NewClient(t).GetRouteInfo("TopicA"); responseCode(err) != pb.ResponseCode_TOPIC_NOT_EXIST {
+		t.Fatalf("route of expired broker-a: %v", err)
+	}
+}
+
+func TestScheduledScanExpiresBrokers(t *testing.T) {
+	t.Parallel()
+	s := NewServer(t)
+	s.RegisterBroker(t, Broker{Cluster: "c", Name: "broker-a", Addr: "10.0.0.1:10911", Topics: []string{"TopicA"}})
+
+	// The broker expires after 5s, the scan runs every 10s
+	s.Advance(10 * time.Second)
+	if expired := s.WaitScan(t); expired != 1 {
+		t.Fatalf("scheduled scan expired %d brokers, want broker-a", expired)
+	}
+	if brokers, _, _ := s.Control.RouteInfo.Counts(); brokers != 0 {
+		t.Fatalf("%d brokers after the scheduled scan", brokers)
+	}
+}
+
+func TestServedLikeTheNameServer(t *testing.T) {
+	t.Parallel()
+	s := NewServer(t)
+	c := s.NewClient(t)
+
+	if _, err := c.Invoke(pb.RequestCode(10000), nil, nil); responseCode(err) != pb.ResponseCode_REQUEST_CODE_NOT_SUPPORTED {
+		t.Fatalf("unknown request code: %v", err)
+	}
+	if _, err := c.GetKVConfig("ns", "missing"); responseCode(err) != pb.ResponseCode_QUERY_NOT_FOUND {
+		t.Fatalf("get missing kv config: %v", err)
+	}
+
+	families, err := s.Control.Metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(&b, family); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !strings.Contains(b.String(), `rocketmq_namesrv_requests_total{code="GET_KV_CONFIG",response_code="QUERY_NOT_FOUND"} 1`) {
+		t.Fatalf("request not counted:\n%s", b.String())
+	}
+}
@@ -0,0 +1,219 @@
+// Package namesrvtest runs a name server over an in-memory listener, for
+// hermetic tests of the processor and the route logic. Every server has its
+// own state and files, so tests using it may run in parallel.
+package namesrvtest
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc/test/bufconn"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"rocketmq-go/common"
+	"rocketmq-go/common/clock"
+	pb "rocketmq-go/common/proto"
+	"rocketmq-go/namesrv"
+	"rocketmq-go/namesrv/config"
+	"rocketmq-go/namesrv/control"
+	"rocketmq-go/remote"
+	"testing"
+	"time"
+)
+
+const (
+	bufSize         = 1 << 20
+	shutdownTimeout = 5 * time.Second
+	// waitTimeout fails the waits for the name server, which only exceed
+	// it when broken
+	waitTimeout = 10 * time.Second
+)
+
+// Server is a namesrv.Server, shut down at the end of the test which
+// started it.
+type Server struct {
+	// Control is the state of the server, e.g. its route info.
+	Control *control.Control
+	// Clock is the clock of the route info and the scheduled tasks, it
+	// starts at the time of NewServer.
+	Clock *clock.Fake
+
+	listener *listener
+	scans    chan int
+}
+
+// Config returns the defaults with the files of the server in a temporary
+// directory, removed at the end of t.
+func Config(t testing.TB) *config.Config {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "namesrvtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	conf := config.Default()
+	conf.KVConfigPath = filepath.Join(dir, "kvConfig.json")
+	conf.ConfigStorePath = filepath.Join(dir, "namesrv.properties")
+	return conf
+}
+
+// NewServer starts a name server with the config of Config.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	return NewServerWithConfig(t, Config(t))
+}
+
+// NewServerWithConfig starts a namesrv.Server with conf, its gRPC API is
+// served in memory instead of ListenAddr.
+func NewServerWithConfig(t testing.TB, conf *config.Config) *Server {
+	t.Helper()
+	srv, err := namesrv.NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		Control:  srv.Control(),
+		Clock:    clock.NewFake(time.Now()),
+		listener: &listener{Listener: bufconn.Listen(bufSize)},
+		scans:    make(chan int, 64),
+	}
+	s.Control.SetClock(s.Clock)
+	s.Control.OnScanNotActiveBroker(func(expired int) {
+		select {
+		case s.scans <- expired:
+		default:
+		}
+	})
+	srv.Listener = s.listener
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			t.Errorf("shutdown name server: %v", err)
+		}
+	})
+
+	return s
+}
+
+// Advance moves the clock of s forward by d. The scheduled tasks due run in
+// the background, see ScanNotActiveBroker to expire the brokers right away.
+func (s *Server) Advance(d time.Duration) {
+	s.Clock.Advance(d)
+}
+
+// WaitScan waits for the next scheduled scan for not active brokers, e.g.
+// run by Advance, and returns how many brokers it removed.
+func (s *Server) WaitScan(t testing.TB) int {
+	t.Helper()
+	select {
+	case expired := <-s.scans:
+		return expired
+	case <-time.After(waitTimeout):
+		t.Fatal("no scheduled scan for not active brokers")
+		return 0
+	}
+}
+
+// ScanNotActiveBroker removes the brokers expired by the clock of s and
+// returns how many it removed, like the scheduled scan does.
+func (s *Server) ScanNotActiveBroker() int {
+	return s.Control.RouteInfo.ScanNotActiveBroker()
+}
+
+// NewClient returns a started client of s, stopped at the end of t.
+func (s *Server) NewClient(t testing.TB) *Client {
+	t.Helper()
+	c := s.newClient()
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Stop)
+	return &Client{Client: c}
+}
+
+// newClient returns a client of s, not started.
+func (s *Server) newClient() *remote.Client {
+	c := remote.NewClient("bufconn")
+	c.Dialer = func(ctx context.Context, addr string) (net.Conn, error) {
+		return s.listener.Dial()
+	}
+	return c
+}
+
+// Subscribe subscribes the route of topics over a client of its own and
+// returns the topics of the NOTIFY_TOPIC_ROUTE_CHANGED pushed to it.
+func (s *Server) Subscribe(t testing.TB, topics ...string) <-chan string {
+	t.Helper()
+	notified := make(chan string, 64)
+	c := s.newClient()
+	c.Processor = func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		header := &pb.NotifyTopicRouteChangedRequestHeader{}
+		if request.Code == int32(pb.RequestCode_NOTIFY_TOPIC_ROUTE_CHANGED) &&
+			common.Deserializable(request.Header, header, false) == nil {
+			notified <- header.Topic
+		}
+		return nil
+	}
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Stop)
+	if err := c.SubscribeTopicRoute(context.Background(), topics, requestTimeout); err != nil {
+		t.Fatal(err)
+	}
+	return notified
+}
+
+// RegisterBroker registers b over a client of its own and returns the
+// client, stopping it drops b like a broker going away. It fails t when b
+// fails to register.
+func (s *Server) RegisterBroker(t testing.TB, b Broker) *Client {
+	t.Helper()
+	c := s.NewClient(t)
+	if _, err := c.RegisterBroker(b); err != nil {
+		t.Fatalf("register broker %s: %v", b.Name, err)
+	}
+	return c
+}
+
+// listener tells the connections it accepts apart by their remote address,
+// the name server keys the channels of brokers on it.
+type listener struct {
+	*bufconn.Listener
+	conns atomic.Int32
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &namedConn{Conn: conn, remoteAddr: addr(fmt.Sprintf("bufconn:%d", l.conns.Inc()))}, nil
+}
+
+type namedConn struct {
+	net.Conn
+	remoteAddr net.Addr
+}
+
+func (c *namedConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+type addr string
+
+func (a addr) Network() string {
+	return "bufconn"
+}
+
+func (a addr) String() string {
+	return string(a)
+}
@@ -0,0 +1,163 @@
+package notifier
+
+import (
+	"go.uber.org/zap"
+	. "rocketmq-go/common"
+	pb "rocketmq-go/common/proto"
+	. "rocketmq-go/common/proto/route"
+	. "rocketmq-go/logging"
+	"rocketmq-go/remote"
+	"sync"
+)
+
+type RouteDataFunc func(topic string) *TopicRouteData
+
+// Notifier pushes NOTIFY_TOPIC_ROUTE_CHANGED to the channels which subscribed
+// the topic whose route changed. Every channel has a worker pushing its
+// changed topics one at a time with the route at the time of the push, so
+// a channel never gets an older route after a newer one.
+type Notifier struct {
+	rw sync.RWMutex
+
+	subscribeTable map[remote.Channel]*subscriber
+	routeData      RouteDataFunc
+}
+
+// subscriber is the state of a channel, guarded by the lock of the Notifier.
+type subscriber struct {
+	topics map[string]bool
+	// topics changed and not pushed yet, in the order they changed
+	pending    []string
+	pendingSet map[string]bool
+	wakeup     chan struct{}
+}
+
+func NewNotifier(routeData RouteDataFunc) *Notifier {
+	return &Notifier{
+		subscribeTable: make(map[remote.Channel]*subscriber),
+		routeData:      routeData,
+	}
+}
+
+func (n *Notifier) Subscribe(ch remote.Channel, topics []string) {
+	n.rw.Lock()
+	defer n.rw.Unlock()
+
+	sub, ok := n.subscribeTable[ch]
+	if !ok {
+		sub = &subscriber{
+			topics:     make(map[string]bool),
+			pendingSet: make(map[string]bool),
+			wakeup:     make(chan struct{}, 1),
+		}
+		n.subscribeTable[ch] = sub
+		go n.work(ch, sub)
+	}
+
+	for _, topic := range topics {
+		sub.topics[topic] = true
+	}
+	Log.Info("subscribe topic route",
+		zap.String("addr", ch.RemoteAddr()),
+		zap.Strings("topics", topics))
+}
+
+func (n *Notifier) UnSubscribe(ch remote.Channel, topics []string) {
+	n.rw.Lock()
+	defer n.rw.Unlock()
+
+	sub, ok := n.subscribeTable[ch]
+	if !ok {
+		return
+	}
+
+	for _, topic := range topics {
+		delete(sub.topics, topic)
+	}
+	Log.Info("unsubscribe topic route",
+		zap.String("addr", ch.RemoteAddr()),
+		zap.Strings("topics", topics))
+}
+
+func (n *Notifier) removeChannel(ch remote.Channel) {
+	n.rw.Lock()
+	defer n.rw.Unlock()
+
+	delete(n.subscribeTable, ch)
+	Log.Debug("remove subscribe channel", zap.String("addr", ch.RemoteAddr()))
+}
+
+// OnTopicRouteChanged is a routeinfo.TopicRouteListener, it only queues the
+// topics to the workers as the route tables are locked while it is called.
+func (n *Notifier) OnTopicRouteChanged(topics []string) {
+	n.rw.Lock()
+	defer n.rw.Unlock()
+
+	for _, sub := range n.subscribeTable {
+		queued := false
+		for _, topic := range topics {
+			if sub.topics[topic] && !sub.pendingSet[topic] {
+				sub.pendingSet[topic] = true
+				sub.pending = append(sub.pending, topic)
+				queued = true
+			}
+		}
+		if queued {
+			select {
+			case sub.wakeup <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// work pushes the changed topics of ch until it is done.
+func (n *Notifier) work(ch remote.Channel, sub *subscriber) {
+	for {
+		select {
+		case <-ch.Done():
+			n.removeChannel(ch)
+			return
+		case <-sub.wakeup:
+		}
+
+		// Topics unsubscribed meanwhile are dropped
+		n.rw.Lock()
+		topics := make([]string, 0, len(sub.pending))
+		for _, topic := range sub.pending {
+			if sub.topics[topic] {
+				topics = append(topics, topic)
+			}
+		}
+		sub.pending = nil
+		sub.pendingSet = make(map[string]bool)
+		n.rw.Unlock()
+
+		for _, topic := range topics {
+			if err := ch.Send(n.createNotifyCommand(topic)); err != nil {
+				Log.Warn("notify topic route changed failed",
+					zap.String("addr", ch.RemoteAddr()),
+					zap.String("topic", topic),
+					zap.Error(err))
+				break
+			}
+		}
+	}
+}
+
+func (n *Notifier) createNotifyCommand(topic string) *pb.RemoteCommand {
+	header := &pb.NotifyTopicRouteChangedRequestHeader{
+		Topic: topic,
+	}
+	cmd := &pb.RemoteCommand{
+		Code:   int32(pb.RequestCode_NOTIFY_TOPIC_ROUTE_CHANGED),
+		Header: Serializable(header),
+	}
+	remote.MarkOnewayRPC(cmd)
+
+	topicRouteData := n.routeData(topic)
+	if topicRouteData != nil {
+		cmd.Body = topicRouteData.Encode()
+	}
+	return cmd
+}
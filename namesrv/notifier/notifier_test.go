@@ -0,0 +1,161 @@
+package notifier
+
+import (
+	"encoding/json"
+	. "rocketmq-go/common"
+	pb "rocketmq-go/common/proto"
+	. "rocketmq-go/common/proto/route"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeChannel struct {
+	sent chan *pb.RemoteCommand
+	done chan struct{}
+}
+
+func newFakeChannel() *fakeChannel {
+	return &fakeChannel{
+		sent: make(chan *pb.RemoteCommand, 10),
+		done: make(chan struct{}),
+	}
+}
+
+func (c *fakeChannel) RemoteAddr() string {
+	return "127.0.0.1:10000"
+}
+
+func (c *fakeChannel) Send(cmd *pb.RemoteCommand) error {
+	c.sent <- cmd
+	return nil
+}
+
+func (c *fakeChannel) Done() <-chan struct{} {
+	return c.done
+}
+
+func TestNotifyOnlySubscribedTopics(t *testing.T) {
+	n := NewNotifier(func(topic string) *TopicRouteData {
+		return &TopicRouteData{}
+	})
+	ch := newFakeChannel()
+	n.Subscribe(ch, []string{"TopicA"})
+
+	n.OnTopicRouteChanged([]string{"TopicB", "TopicA"})
+
+	select {
+	case cmd := <-ch.sent:
+		if cmd.Code != int32(pb.RequestCode_NOTIFY_TOPIC_ROUTE_CHANGED) {
+			t.Fatalf("code = %d", cmd.Code)
+		}
+		header := &pb.NotifyTopicRouteChangedRequestHeader{}
+		if err := Deserializable(cmd.Header, header, false); err != nil {
+			t.Fatal(err)
+		}
+		if header.Topic != "TopicA" {
+			t.Fatalf("topic = %s, want TopicA", header.Topic)
+		}
+		if !json.Valid(cmd.Body) {
+			t.Fatalf("invalid body: %s", cmd.Body)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no notification pushed")
+	}
+
+	select {
+	case cmd := <-ch.sent:
+		t.Fatalf("unexpected notification: %v", cmd)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestChannelRemovedWhenDone(t *testing.T) {
+	n := NewNotifier(func(topic string) *TopicRouteData {
+		return nil
+	})
+	ch := newFakeChannel()
+	n.Subscribe(ch, []string{"TopicA"})
+	close(ch.done)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		n.rw.RLock()
+		_, ok := n.subscribeTable[ch]
+		n.rw.RUnlock()
+		if !ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("channel not removed after done")
+}
+
+func TestNotifyInOrderWithCurrentRoute(t *testing.T) {
+	var version int64
+	n := NewNotifier(func(topic string) *TopicRouteData {
+		return &TopicRouteData{OrderTopicConf: strconv.FormatInt(atomic.LoadInt64(&version), 10)}
+	})
+	ch := newFakeChannel()
+	n.Subscribe(ch, []string{"TopicA"})
+
+	const changes = 100
+	for i := 0; i < changes; i++ {
+		atomic.AddInt64(&version, 1)
+		n.OnTopicRouteChanged([]string{"TopicA"})
+	}
+
+	last := int64(0)
+	for last < changes {
+		select {
+		case cmd := <-ch.sent:
+			routeData, err := DecodeTopicRouteData(cmd.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pushed, _ := strconv.ParseInt(routeData.OrderTopicConf, 10, 64)
+			if pushed <= last {
+				t.Fatalf("pushed route %d after %d", pushed, last)
+			}
+			last = pushed
+		case <-time.After(time.Second):
+			t.Fatalf("last pushed route %d, want %d", last, changes)
+		}
+	}
+}
+
+func TestNotifySkipsUnsubscribedTopics(t *testing.T) {
+	n := NewNotifier(func(topic string) *TopicRouteData {
+		return &TopicRouteData{}
+	})
+	ch := newFakeChannel()
+	n.Subscribe(ch, []string{"TopicA", "TopicB"})
+
+	// Hold the worker until both changes are queued
+	n.rw.Lock()
+	sub := n.subscribeTable[ch]
+	sub.pending = append(sub.pending, "TopicA", "TopicB")
+	sub.pendingSet["TopicA"], sub.pendingSet["TopicB"] = true, true
+	delete(sub.topics, "TopicA")
+	sub.wakeup <- struct{}{}
+	n.rw.Unlock()
+
+	select {
+	case cmd := <-ch.sent:
+		header := &pb.NotifyTopicRouteChangedRequestHeader{}
+		if err := Deserializable(cmd.Header, header, false); err != nil {
+			t.Fatal(err)
+		}
+		if header.Topic != "TopicB" {
+			t.Fatalf("topic = %s, want TopicB", header.Topic)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no notification pushed")
+	}
+	select {
+	case cmd := <-ch.sent:
+		t.Fatalf("unexpected notification: %v", cmd)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -2,12 +2,15 @@ package processor
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"go.uber.org/zap"
 	. "rocketmq-go/common"
 	pb "rocketmq-go/common/proto"
 	. "rocketmq-go/logging"
+	. "rocketmq-go/namesrv/config"
 	. "rocketmq-go/namesrv/control"
+	"rocketmq-go/remote"
 )
 
 type process func(context.Context, *pb.RemoteCommand) *pb.RemoteCommand
@@ -44,22 +47,32 @@ func NewDefaultProcessor(c *Control) *DefaultProcessor {
 	m[pb.RequestCode_GET_HAS_UNIT_SUB_UNUNIT_TOPIC_LIST] = p.getHasUnitSubUnUnitTopicList
 	m[pb.RequestCode_UPDATE_NAMESRV_CONFIG] = p.updateConfig
 	m[pb.RequestCode_GET_NAMESRV_CONFIG] = p.getConfig
+	m[pb.RequestCode_ADD_WRITE_PERM_OF_BROKER] = p.addWritePermOfBroker
+	m[pb.RequestCode_SUBSCRIBE_TOPIC_ROUTE] = p.subscribeTopicRoute
+	m[pb.RequestCode_UNSUBSCRIBE_TOPIC_ROUTE] = p.unSubscribeTopicRoute
 	return &p
 }
 
 func (d *DefaultProcessor) Process(
 	ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
-	Log.Debug("receive request",
-		zap.Int32("code", request.Code),
-		zap.String("addr", GetRemoteAddr(ctx)))
-
 	process, ok := d.process[pb.RequestCode(request.Code)]
 	if ok {
 		return process(ctx, request)
 	}
 
 	Log.Warn("receive request, unknown code", zap.Int32("code", request.Code))
-	return nil
+	return remote.CreateResponseCommand(pb.ResponseCode_REQUEST_CODE_NOT_SUPPORTED,
+		fmt.Sprintf("request code %d not supported", request.Code))
+}
+
+func headerDecodeError(err error) *pb.RemoteCommand {
+	return remote.CreateResponseCommand(pb.ResponseCode_HEADER_DECODE_ERROR,
+		"decode request header failed: "+err.Error())
+}
+
+func bodyDecodeError(err error) *pb.RemoteCommand {
+	return remote.CreateResponseCommand(pb.ResponseCode_SYSTEM_ERROR,
+		"decode request body failed: "+err.Error())
 }
 
 func checksum(
@@ -73,7 +86,7 @@ func (d *DefaultProcessor) putKVConfig(
 	reqHeader := &pb.PutKVConfigRequestHeader{}
 	err := Deserializable(request.Header, reqHeader, false)
 	if err != nil {
-		return nil
+		return headerDecodeError(err)
 	}
 
 	d.Control.KVConfig.PutKVConfig(reqHeader.Namespace, reqHeader.Key, reqHeader.Value)
@@ -88,7 +101,7 @@ func (d *DefaultProcessor) getKVConfig(
 	reqHeader := &pb.GetKVConfigRequestHeader{}
 	err := Deserializable(request.Header, reqHeader, false)
 	if err != nil {
-		return nil
+		return headerDecodeError(err)
 	}
 
 	value := d.Control.KVConfig.GetKVConfig(reqHeader.Namespace, reqHeader.Key)
@@ -114,7 +127,7 @@ func (d *DefaultProcessor) deleteKVConfig(
 	reqHeader := &pb.DeleteKVConfigRequestHeader{}
 	err := Deserializable(request.Header, reqHeader, false)
 	if err != nil {
-		return nil
+		return headerDecodeError(err)
 	}
 
 	d.Control.KVConfig.DeleteKVConfig(reqHeader.Namespace, reqHeader.Key)
@@ -127,11 +140,35 @@ func (d *DefaultProcessor) queryBrokerTopicConfig(
 	ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
 	response := &pb.RemoteCommand{}
 	reqHeader := &pb.QueryDataVersionRequestHeader{}
+	body := &pb.DataVersion{}
 	err := Deserializable(request.Header, reqHeader, false)
 	if err != nil {
-		return nil
+		return headerDecodeError(err)
+	}
+
+	err = Deserializable(request.Body, body, false)
+	if err != nil {
+		return bodyDecodeError(err)
+	}
+
+	dataVersion := NewDataVersionFromProto(body)
+	changed := d.Control.RouteInfo.IsBrokerTopicConfigChanged(reqHeader.BrokerAddr, dataVersion)
+	if !changed {
+		d.Control.RouteInfo.UpdateBrokerInfoUpdateTimestamp(reqHeader.BrokerAddr)
+	}
+
+	nameSrvDataVersion := d.Control.RouteInfo.QueryBrokerTopicConfig(reqHeader.BrokerAddr)
+	if nameSrvDataVersion != nil {
+		response.Body = Serializable(nameSrvDataVersion.ToProto())
 	}
 
+	respHeader := &pb.QueryDataVersionResponseHeader{
+		Changed: changed,
+	}
+	byteHeader := Serializable(respHeader)
+
+	response.Code = int32(pb.ResponseCode_SUCCESS)
+	response.Header = byteHeader
 	return response
 }
 
@@ -142,12 +179,12 @@ func (d *DefaultProcessor) registerBrokerWithFilterServer(
 	body := &pb.RegisterBrokerBody{}
 	err := Deserializable(request.Header, reqHeader, false)
 	if err != nil {
-		return nil
+		return headerDecodeError(err)
 	}
 
 	err = Deserializable(request.Body, body, false)
 	if err != nil {
-		return nil
+		return bodyDecodeError(err)
 	}
 
 	if !checksum(ctx, request, reqHeader) {
@@ -162,7 +199,9 @@ func (d *DefaultProcessor) registerBrokerWithFilterServer(
 		reqHeader.BrokerName,
 		reqHeader.BrokerId,
 		reqHeader.HaServerAddr,
-		&body.FilterServerList)
+		body.TopicConfigSerializeWrapper,
+		&body.FilterServerList,
+		GetRemoteAddr(ctx))
 
 
 	respHeader := &pb.RegisterBrokerResponseHeader{
@@ -182,7 +221,7 @@ func (d *DefaultProcessor) unRegisterBroker(
 	reqHeader := &pb.UnRegisterBrokerHeader{}
 	err := Deserializable(request.Header, reqHeader, false)
 	if err != nil {
-		return nil
+		return headerDecodeError(err)
 	}
 
 	d.Control.RouteInfo.UnRegisterBroker(
@@ -201,14 +240,13 @@ func (d *DefaultProcessor) getRouteInfoByTopic(
 	reqHeader := &pb.GetRouteInfoRequestHeader{}
 	err := Deserializable(request.Header, reqHeader, false)
 	if err != nil {
-		return nil
+		return headerDecodeError(err)
 	}
 
-	topicRouteData := d.Control.RouteInfo.PickupTopicRouteData(reqHeader.Topic)
+	topicRouteData := d.Control.PickupTopicRouteData(reqHeader.Topic)
 	if topicRouteData != nil {
-		content, _ := json.Marshal(topicRouteData)
 		response.Code = int32(pb.ResponseCode_SUCCESS)
-		response.Body = content
+		response.Body = topicRouteData.Encode()
 		return response
 	}
 
@@ -224,26 +262,64 @@ func (d *DefaultProcessor) getBrokerClusterInfo(
 	body := d.Control.RouteInfo.GetAllClusterInfo()
 
 	response.Body = body
-	response.Code = int32(pb.ResponseCode_TOPIC_NOT_EXIST)
+	response.Code = int32(pb.ResponseCode_SUCCESS)
 	return response
 }
 
 func (d *DefaultProcessor) wipeWritePermOfBroker(
 	ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
-	//response := &pb.RemoteCommand{}
-	//reqHeader := &pb.WipeWritePermOfBrokerRequestHeader{}
-	//err := Deserializable(request.Header, reqHeader, false)
-	//if err != nil {
-	//	return nil
-	//}
-
-	return nil
+	response := &pb.RemoteCommand{}
+	reqHeader := &pb.WipeWritePermOfBrokerRequestHeader{}
+	err := Deserializable(request.Header, reqHeader, false)
+	if err != nil {
+		return headerDecodeError(err)
+	}
+
+	wipeTopicCnt := d.Control.RouteInfo.WipeWritePermOfBroker(reqHeader.BrokerName)
+	Log.Info("wipe write perm of broker",
+		zap.String("addr", GetRemoteAddr(ctx)),
+		zap.String("brokerName", reqHeader.BrokerName),
+		zap.Int("wipeTopicCount", wipeTopicCnt))
+
+	respHeader := &pb.WipeWritePermOfBrokerResponseHeader{
+		WipeTopicCount: int32(wipeTopicCnt),
+	}
+	byteHeader := Serializable(respHeader)
+
+	response.Code = int32(pb.ResponseCode_SUCCESS)
+	response.Header = byteHeader
+	return response
+}
+
+func (d *DefaultProcessor) addWritePermOfBroker(
+	ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+	response := &pb.RemoteCommand{}
+	reqHeader := &pb.AddWritePermOfBrokerRequestHeader{}
+	err := Deserializable(request.Header, reqHeader, false)
+	if err != nil {
+		return headerDecodeError(err)
+	}
+
+	addTopicCnt := d.Control.RouteInfo.AddWritePermOfBroker(reqHeader.BrokerName)
+	Log.Info("add write perm of broker",
+		zap.String("addr", GetRemoteAddr(ctx)),
+		zap.String("brokerName", reqHeader.BrokerName),
+		zap.Int("addTopicCount", addTopicCnt))
+
+	respHeader := &pb.AddWritePermOfBrokerResponseHeader{
+		AddTopicCount: int32(addTopicCnt),
+	}
+	byteHeader := Serializable(respHeader)
+
+	response.Code = int32(pb.ResponseCode_SUCCESS)
+	response.Header = byteHeader
+	return response
 }
 
 func (d *DefaultProcessor) getAllTopicListFromNameServer(
 	ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
 	response := &pb.RemoteCommand{}
-	body := d.Control.RouteInfo.GetAllClusterInfo()
+	body := d.Control.RouteInfo.GetAllTopicList()
 
 	response.Body = body
 	response.Code = int32(pb.ResponseCode_SUCCESS)
@@ -256,7 +332,7 @@ func (d *DefaultProcessor) deleteTopicInNameServer(
 	reqHeader := &pb.DeleteTopicInNamesrvRequestHeader{}
 	err := Deserializable(request.Header, reqHeader, false)
 	if err != nil {
-		return nil
+		return headerDecodeError(err)
 	}
 
 	d.Control.RouteInfo.DeleteTopic(reqHeader.Topic)
@@ -271,7 +347,7 @@ func (d *DefaultProcessor) getKVListByNamespace(
 	reqHeader := &pb.GetKVListByNamespaceRequestHeader{}
 	err := Deserializable(request.Header, reqHeader, false)
 	if err != nil {
-		return nil
+		return headerDecodeError(err)
 	}
 
 	body := d.Control.KVConfig.GetKVListByNamespace(reqHeader.Namespace)
@@ -292,7 +368,7 @@ func (d *DefaultProcessor) getTopicsByCluster(
 	reqHeader := &pb.GetTopicsByClusterRequestHeader{}
 	err := Deserializable(request.Header, reqHeader, false)
 	if err != nil {
-		return nil
+		return headerDecodeError(err)
 	}
 
 	body := d.Control.RouteInfo.GetTopicByCluster(reqHeader.Cluster)
@@ -342,17 +418,81 @@ func (d *DefaultProcessor) updateConfig(
 	ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
 	Log.Sugar().Infof("updateConfig called by %s", GetRemoteAddr(ctx))
 
-	//response := &pb.RemoteCommand{}
-	//body := request.Body
-	//if body != nil {
-	//	bodyStr := string(body)
-	//}
-	//
-	//response.Code = int32(pb.ResponseCode_SUCCESS)
-	return nil
+	response := &pb.RemoteCommand{}
+	properties, err := ParseProperties(string(request.Body))
+	if err != nil {
+		response.Code = int32(pb.ResponseCode_SYSTEM_ERROR)
+		response.Remark = "string2Properties error: " + err.Error()
+		return response
+	}
+
+	err = d.Control.UpdateConfig(properties)
+	if err != nil {
+		if errors.Is(err, ErrStaticKey) {
+			response.Code = int32(pb.ResponseCode_NO_PERMISSION)
+		} else {
+			response.Code = int32(pb.ResponseCode_SYSTEM_ERROR)
+		}
+		response.Remark = err.Error()
+		return response
+	}
+
+	err = d.Control.NameSrvConf.Persist()
+	if err != nil {
+		Log.Error("persist name server config failed", zap.Error(err))
+		response.Code = int32(pb.ResponseCode_SYSTEM_ERROR)
+		response.Remark = "config updated but persist failed: " + err.Error()
+		return response
+	}
+
+	response.Code = int32(pb.ResponseCode_SUCCESS)
+	return response
 }
 
 func (d *DefaultProcessor) getConfig(
 	ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
-	return nil
-}
\ No newline at end of file
+	response := &pb.RemoteCommand{}
+	response.Body = []byte(d.Control.NameSrvConf.Properties())
+	response.Code = int32(pb.ResponseCode_SUCCESS)
+	return response
+}
+
+func (d *DefaultProcessor) subscribeTopicRoute(
+	ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+	response := &pb.RemoteCommand{}
+	reqHeader := &pb.SubscribeTopicRouteRequestHeader{}
+	err := Deserializable(request.Header, reqHeader, false)
+	if err != nil {
+		return headerDecodeError(err)
+	}
+
+	ch := remote.ChannelFromContext(ctx)
+	if ch == nil {
+		response.Code = int32(pb.ResponseCode_SYSTEM_ERROR)
+		response.Remark = "no channel to push topic route changes to"
+		return response
+	}
+
+	d.Control.Notifier.Subscribe(ch, reqHeader.Topics)
+
+	response.Code = int32(pb.ResponseCode_SUCCESS)
+	return response
+}
+
+func (d *DefaultProcessor) unSubscribeTopicRoute(
+	ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+	response := &pb.RemoteCommand{}
+	reqHeader := &pb.UnSubscribeTopicRouteRequestHeader{}
+	err := Deserializable(request.Header, reqHeader, false)
+	if err != nil {
+		return headerDecodeError(err)
+	}
+
+	ch := remote.ChannelFromContext(ctx)
+	if ch != nil {
+		d.Control.Notifier.UnSubscribe(ch, reqHeader.Topics)
+	}
+
+	response.Code = int32(pb.ResponseCode_SUCCESS)
+	return response
+}
@@ -0,0 +1,276 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"rocketmq-go/common"
+	"rocketmq-go/common/clock"
+	"rocketmq-go/common/perm"
+	pb "rocketmq-go/common/proto"
+	route "rocketmq-go/common/proto/route"
+	"rocketmq-go/namesrv/config"
+	"rocketmq-go/namesrv/control"
+	"rocketmq-go/namesrv/kvconfig"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestProcessor(t *testing.T) *DefaultProcessor {
+	dir, err := ioutil.TempDir("", "processor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	conf := config.Default()
+	conf.KVConfigPath = filepath.Join(dir, "kvConfig.json")
+	conf.ConfigStorePath = filepath.Join(dir, "namesrv.properties")
+	conf.ListenAddr = "127.0.0.1:0"
+	ctl := control.NewControl(conf)
+	return NewDefaultProcessor(ctl)
+}
+
+func TestMalformedHeaderGetsResponse(t *testing.T) {
+	decodeError := pb.ResponseCode_HEADER_DECODE_ERROR
+	success := pb.ResponseCode_SUCCESS
+	tests := map[pb.RequestCode]pb.ResponseCode{
+		pb.RequestCode_PUT_KV_CONFIG:                      decodeError,
+		pb.RequestCode_GET_KV_CONFIG:                      decodeError,
+		pb.RequestCode_DELETE_KV_CONFIG:                   decodeError,
+		pb.RequestCode_QUERY_DATA_VERSION:                 decodeError,
+		pb.RequestCode_REGISTER_BROKER:                    decodeError,
+		pb.RequestCode_UNREGISTER_BROKER:                  decodeError,
+		pb.RequestCode_GET_ROUTEINFO_BY_TOPIC:             decodeError,
+		pb.RequestCode_GET_BROKER_CLUSTER_INFO:            success,
+		pb.RequestCode_WIPE_WRITE_PERM_OF_BROKER:          decodeError,
+		pb.RequestCode_GET_ALL_TOPIC_LIST_FROM_NAMESERVER: success,
+		pb.RequestCode_DELETE_TOPIC_IN_NAMESRV:            decodeError,
+		pb.RequestCode_GET_KVLIST_BY_NAMESPACE:            decodeError,
+		pb.RequestCode_GET_TOPICS_BY_CLUSTER:              decodeError,
+		pb.RequestCode_GET_SYSTEM_TOPIC_LIST_FROM_NS:      success,
+		pb.RequestCode_GET_UNIT_TOPIC_LIST:                success,
+		pb.RequestCode_GET_HAS_UNIT_SUB_TOPIC_LIST:        success,
+		pb.RequestCode_GET_HAS_UNIT_SUB_UNUNIT_TOPIC_LIST: success,
+		pb.RequestCode_UPDATE_NAMESRV_CONFIG:              success,
+		pb.RequestCode_GET_NAMESRV_CONFIG:                 success,
+		pb.RequestCode_ADD_WRITE_PERM_OF_BROKER:           decodeError,
+		pb.RequestCode_SUBSCRIBE_TOPIC_ROUTE:              decodeError,
+		pb.RequestCode_UNSUBSCRIBE_TOPIC_ROUTE:            decodeError,
+		pb.RequestCode_NOTIFY_TOPIC_ROUTE_CHANGED:         pb.ResponseCode_REQUEST_CODE_NOT_SUPPORTED,
+		pb.RequestCode_NOTIFY_SHUTDOWN:                    pb.ResponseCode_REQUEST_CODE_NOT_SUPPORTED,
+		pb.RequestCode(10000):                             pb.ResponseCode_REQUEST_CODE_NOT_SUPPORTED,
+	}
+	for code, name := range pb.RequestCode_name {
+		if _, ok := tests[pb.RequestCode(code)]; !ok {
+			t.Errorf("no test case for %s", name)
+		}
+	}
+
+	p := newTestProcessor(t)
+	for code, want := range tests {
+		request := &pb.RemoteCommand{
+			Code:   int32(code),
+			Header: []byte{0xff, 0xff, 0xff},
+		}
+		response := p.Process(context.Background(), request)
+		if response == nil {
+			t.Errorf("%s: no response", code)
+			continue
+		}
+		if response.Code != int32(want) {
+			t.Errorf("%s: code = %s, want %s, remark: %s",
+				code, pb.ResponseCode(response.Code), want, response.Remark)
+		}
+		if want == decodeError && response.Remark == "" {
+			t.Errorf("%s: no parse error in remark", code)
+		}
+	}
+}
+
+// invoke runs a request of code with header, which may be nil, and fails
+// t unless the response is SUCCESS.
+func invoke(t *testing.T, p *DefaultProcessor, code pb.RequestCode, header proto.Message) *pb.RemoteCommand {
+	t.Helper()
+	request := &pb.RemoteCommand{Code: int32(code)}
+	if header != nil {
+		request.Header = common.Serializable(header)
+	}
+	response := p.Process(context.Background(), request)
+	if response.Code != int32(pb.ResponseCode_SUCCESS) {
+		t.Fatalf("%s: code = %s, remark: %s", code, pb.ResponseCode(response.Code), response.Remark)
+	}
+	return response
+}
+
+func registerBroker(p *DefaultProcessor, brokerName string, brokerAddr string, topics ...string) {
+	table := make(map[string]*pb.TopicConfig)
+	for _, topic := range topics {
+		table[topic] = &pb.TopicConfig{
+			TopicName:      topic,
+			ReadQueueNums:  4,
+			WriteQueueNums: 4,
+			Perm:           perm.PermRead | perm.PermWrite,
+		}
+	}
+	p.Control.RouteInfo.RegisterBroker("cluster", brokerAddr, brokerName, 0, "",
+		&pb.TopicConfigSerializeWrapper{
+			TopicConfigTable: table,
+			DataVersion:      &pb.DataVersion{Timestamp: 1, Counter: 1},
+		}, nil, "127.0.0.1:50000")
+}
+
+func TestWritePermOfBroker(t *testing.T) {
+	p := newTestProcessor(t)
+	registerBroker(p, "broker-a", "127.0.0.1:10911", "TopicA", "TopicB")
+
+	response := invoke(t, p, pb.RequestCode_WIPE_WRITE_PERM_OF_BROKER,
+		&pb.WipeWritePermOfBrokerRequestHeader{BrokerName: "broker-a"})
+	wipeHeader := &pb.WipeWritePermOfBrokerResponseHeader{}
+	if err := common.Deserializable(response.Header, wipeHeader, false); err != nil {
+		t.Fatal(err)
+	}
+	if wipeHeader.WipeTopicCount != 2 {
+		t.Fatalf("wipe topic count = %d, want 2", wipeHeader.WipeTopicCount)
+	}
+	if queueData := p.Control.PickupTopicRouteData("TopicA").QueueDatas[0]; queueData.Perm != perm.PermRead {
+		t.Fatalf("perm after wipe = %d", queueData.Perm)
+	}
+
+	response = invoke(t, p, pb.RequestCode_ADD_WRITE_PERM_OF_BROKER,
+		&pb.AddWritePermOfBrokerRequestHeader{BrokerName: "broker-a"})
+	addHeader := &pb.AddWritePermOfBrokerResponseHeader{}
+	if err := common.Deserializable(response.Header, addHeader, false); err != nil {
+		t.Fatal(err)
+	}
+	if addHeader.AddTopicCount != 2 {
+		t.Fatalf("add topic count = %d, want 2", addHeader.AddTopicCount)
+	}
+	if queueData := p.Control.PickupTopicRouteData("TopicA").QueueDatas[0]; queueData.Perm != perm.PermRead|perm.PermWrite {
+		t.Fatalf("perm after add = %d", queueData.Perm)
+	}
+
+	response = invoke(t, p, pb.RequestCode_WIPE_WRITE_PERM_OF_BROKER,
+		&pb.WipeWritePermOfBrokerRequestHeader{BrokerName: "broker-c"})
+	wipeHeader = &pb.WipeWritePermOfBrokerResponseHeader{}
+	if err := common.Deserializable(response.Header, wipeHeader, false); err != nil {
+		t.Fatal(err)
+	}
+	if wipeHeader.WipeTopicCount != 0 {
+		t.Fatalf("wipe topic count of unknown broker = %d", wipeHeader.WipeTopicCount)
+	}
+}
+
+func TestRouteInfoOrderTopicConf(t *testing.T) {
+	p := newTestProcessor(t)
+	p.Control.NameSrvConf.OrderMessageEnable = true
+	registerBroker(p, "broker-a", "127.0.0.1:10911", "TopicA", "TopicB")
+	p.Control.KVConfig.PutKVConfig(kvconfig.NamespaceOrderTopicConfig, "TopicA", "broker-a:4;broker-b:4")
+
+	routeOf := func(topic string) (*route.TopicRouteData, string) {
+		response := invoke(t, p, pb.RequestCode_GET_ROUTEINFO_BY_TOPIC, &pb.GetRouteInfoRequestHeader{Topic: topic})
+		routeData, err := route.DecodeTopicRouteData(response.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return routeData, string(response.Body)
+	}
+
+	if routeData, _ := routeOf("TopicA"); routeData.OrderTopicConf != "broker-a:4;broker-b:4" {
+		t.Fatalf("orderTopicConf = %q", routeData.OrderTopicConf)
+	}
+	if _, body := routeOf("TopicB"); strings.Contains(body, "orderTopicConf") {
+		t.Fatalf("route without order topic config = %s", body)
+	}
+
+	p.Control.NameSrvConf.OrderMessageEnable = false
+	if _, body := routeOf("TopicA"); strings.Contains(body, "orderTopicConf") {
+		t.Fatalf("route with order message disabled = %s", body)
+	}
+}
+
+func TestQueryDataVersion(t *testing.T) {
+	p := newTestProcessor(t)
+	fake := clock.NewFake(time.Unix(1600000000, 0))
+	p.Control.SetClock(fake)
+	p.Control.RouteInfo.SetBrokerExpiredTime(5000)
+	registerBroker(p, "broker-a", "127.0.0.1:10911", "TopicA")
+
+	query := func(dataVersion *pb.DataVersion) (bool, *pb.DataVersion) {
+		request := &pb.RemoteCommand{
+			Code:   int32(pb.RequestCode_QUERY_DATA_VERSION),
+			Header: common.Serializable(&pb.QueryDataVersionRequestHeader{BrokerAddr: "127.0.0.1:10911"}),
+			Body:   common.Serializable(dataVersion),
+		}
+		response := p.Process(context.Background(), request)
+		if response.Code != int32(pb.ResponseCode_SUCCESS) {
+			t.Fatalf("code = %s, remark: %s", pb.ResponseCode(response.Code), response.Remark)
+		}
+		respHeader := &pb.QueryDataVersionResponseHeader{}
+		if err := common.Deserializable(response.Header, respHeader, false); err != nil {
+			t.Fatal(err)
+		}
+		nameSrvVersion := &pb.DataVersion{}
+		if err := common.Deserializable(response.Body, nameSrvVersion, false); err != nil {
+			t.Fatal(err)
+		}
+		return respHeader.Changed, nameSrvVersion
+	}
+
+	fake.Advance(4 * time.Second)
+	changed, nameSrvVersion := query(&pb.DataVersion{Timestamp: 1, Counter: 1})
+	if changed {
+		t.Fatal("registered data version reported changed")
+	}
+	if nameSrvVersion.Timestamp != 1 || nameSrvVersion.Counter != 1 {
+		t.Fatalf("name server data version = %v", nameSrvVersion)
+	}
+	// The unchanged query counts as an update of the broker
+	fake.Advance(4 * time.Second)
+	if expired := p.Control.RouteInfo.ScanNotActiveBroker(); expired != 0 {
+		t.Fatal("broker expired although its query refreshed it")
+	}
+
+	changed, nameSrvVersion = query(&pb.DataVersion{Timestamp: 1, Counter: 2})
+	if !changed {
+		t.Fatal("next data version reported unchanged")
+	}
+	if nameSrvVersion.Timestamp != 1 || nameSrvVersion.Counter != 1 {
+		t.Fatalf("name server data version = %v", nameSrvVersion)
+	}
+	// The changed query does not, the broker has to register again
+	fake.Advance(time.Second + time.Millisecond)
+	if expired := p.Control.RouteInfo.ScanNotActiveBroker(); expired != 1 {
+		t.Fatalf("expired %d brokers after the changed query, want 1", expired)
+	}
+}
+
+func TestUpdateConfigWhileRouting(t *testing.T) {
+	p := newTestProcessor(t)
+	registerBroker(p, "broker-a", "127.0.0.1:10911", "TopicA")
+	p.Control.KVConfig.PutKVConfig(kvconfig.NamespaceOrderTopicConfig, "TopicA", "broker-a:4")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			request := &pb.RemoteCommand{
+				Code: int32(pb.RequestCode_UPDATE_NAMESRV_CONFIG),
+				Body: []byte(fmt.Sprintf("orderMessageEnable=%t", i%2 == 0)),
+			}
+			p.Process(context.Background(), request)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		invoke(t, p, pb.RequestCode_GET_ROUTEINFO_BY_TOPIC, &pb.GetRouteInfoRequestHeader{Topic: "TopicA"})
+	}
+	<-done
+
+	if response := invoke(t, p, pb.RequestCode_GET_NAMESRV_CONFIG, nil); !strings.Contains(string(response.Body), "orderMessageEnable=false\n") {
+		t.Fatalf("config after the updates:\n%s", response.Body)
+	}
+}
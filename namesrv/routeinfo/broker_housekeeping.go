@@ -0,0 +1,32 @@
+package routeinfo
+
+import (
+	"go.uber.org/zap"
+	. "rocketmq-go/logging"
+)
+
+// BrokerHousekeepingService is a remote.ChannelEventListener which drops the
+// route of a broker as soon as its connection goes away.
+type BrokerHousekeepingService struct {
+	routeInfo *RouteInfo
+}
+
+func NewBrokerHousekeepingService(routeInfo *RouteInfo) *BrokerHousekeepingService {
+	return &BrokerHousekeepingService{routeInfo}
+}
+
+func (b *BrokerHousekeepingService) OnChannelConnect(remoteAddr string) {
+}
+
+func (b *BrokerHousekeepingService) OnChannelClose(remoteAddr string) {
+	b.routeInfo.OnChannelDestroy(remoteAddr)
+}
+
+func (b *BrokerHousekeepingService) OnChannelException(remoteAddr string, err error) {
+	Log.Debug("channel exception", zap.String("addr", remoteAddr), zap.Error(err))
+	b.routeInfo.OnChannelDestroy(remoteAddr)
+}
+
+func (b *BrokerHousekeepingService) OnChannelIdle(remoteAddr string) {
+	b.routeInfo.OnChannelDestroy(remoteAddr)
+}
@@ -1,15 +1,24 @@
 package routeinfo
 
+import "rocketmq-go/common"
+
 type BrokerLiveInfo struct {
 	lastUpdateTime int64
-	//dataVersion DataVersion
+	dataVersion *common.DataVersion
 	haServerAddr string
+	channelAddr string		// remote address of the connection the broker registered on
 }
 
-func NewBrokerLiveInfo(lastUpdateTime int64, haServerAddr string) *BrokerLiveInfo {
+func NewBrokerLiveInfo(
+	lastUpdateTime int64,
+	dataVersion *common.DataVersion,
+	haServerAddr string,
+	channelAddr string) *BrokerLiveInfo {
 	return &BrokerLiveInfo{
 		lastUpdateTime: lastUpdateTime,
+		dataVersion: dataVersion,
 		haServerAddr: haServerAddr,
+		channelAddr: channelAddr,
 	}
 }
 
@@ -21,10 +30,28 @@ func (b *BrokerLiveInfo) SetLastUpdateTime(lastUpdateTime int64) {
 	b.lastUpdateTime = lastUpdateTime
 }
 
+// IsExpired reports whether the broker was not updated within expiredTime
+// milliseconds before now.
+func (b *BrokerLiveInfo) IsExpired(now int64, expiredTime int64) bool {
+	return b.lastUpdateTime + expiredTime < now
+}
+
+func (b *BrokerLiveInfo) GetDataVersion() *common.DataVersion {
+	return b.dataVersion
+}
+
+func (b *BrokerLiveInfo) SetDataVersion(dataVersion *common.DataVersion) {
+	b.dataVersion = dataVersion
+}
+
 func (b *BrokerLiveInfo) GetHaServerAddr() string {
 	return b.haServerAddr
 }
 
 func (b *BrokerLiveInfo) SetHaServerAddr(haServerAddr string) {
 	b.haServerAddr = haServerAddr
-}
\ No newline at end of file
+}
+
+func (b *BrokerLiveInfo) GetChannelAddr() string {
+	return b.channelAddr
+}
@@ -2,9 +2,11 @@ package routeinfo
 
 import (
 	"encoding/json"
-	"fmt"
 	"go.uber.org/zap"
 	"rocketmq-go/common"
+	"rocketmq-go/common/clock"
+	"rocketmq-go/common/perm"
+	pb "rocketmq-go/common/proto"
 	. "rocketmq-go/common/proto/route"
 	"rocketmq-go/common/sysflag"
 	. "rocketmq-go/logging"
@@ -15,28 +17,80 @@ const (
 	BrokerExpiredTime = 1000 * 5
 )
 
+// TopicRouteListener is told which topics had their route changed. It is
+// called with the route tables locked, so it must neither block nor call back
+// into RouteInfo synchronously.
+type TopicRouteListener func(topics []string)
+
 type RouteInfo struct {
 	rw sync.RWMutex
 
 	topicQueueTable 	map[string] []QueueData			// map[topic] = Slice[QueueData]
 	brokerAddrTable 	map[string] BrokerData			// map[brokerName] = BrokerData
-	clusterAddrTable 	map[string] map[string]bool 	// map[clusterName] = map[brokerName]
+	clusterAddrTable 	map[string] StringSet 			// map[clusterName] = Set[brokerName]
 	brokerLiveTable 	map[string] BrokerLiveInfo		// map[brokerAddr] = BrokerLiveInfo
 	filterServerTable 	map[string] []string			// map[brokerAddr] = filterServer
+
+	topicRouteListener TopicRouteListener
+	brokerExpiredTime int64
+	clock clock.Clock
 }
 
 func NewRouteInfo() *RouteInfo {
-	var lock sync.RWMutex
 	return &RouteInfo{
-		lock,
-		make(map[string][]QueueData, 1024),
-		make(map[string]BrokerData, 128),
-		make(map[string]map[string]bool, 32),
-		make(map[string]BrokerLiveInfo, 256),
-		make(map[string][]string, 256),
+		topicQueueTable:   make(map[string][]QueueData, 1024),
+		brokerAddrTable:   make(map[string]BrokerData, 128),
+		clusterAddrTable:  make(map[string]StringSet, 32),
+		brokerLiveTable:   make(map[string]BrokerLiveInfo, 256),
+		filterServerTable: make(map[string][]string, 256),
+		brokerExpiredTime: BrokerExpiredTime,
+		clock:             clock.Real,
+	}
+}
+
+// SetClock changes the clock the brokers are stamped and expired with.
+func (r *RouteInfo) SetClock(c clock.Clock) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.clock = c
+}
+
+// SetBrokerExpiredTime changes how long in milliseconds a broker stays
+// registered without updates.
+func (r *RouteInfo) SetBrokerExpiredTime(mills int64) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.brokerExpiredTime = mills
+}
+
+func (r *RouteInfo) SetTopicRouteListener(listener TopicRouteListener) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.topicRouteListener = listener
+}
+
+func (r *RouteInfo) topicRouteChanged(topics []string) {
+	if r.topicRouteListener != nil && len(topics) > 0 {
+		r.topicRouteListener(topics)
 	}
 }
 
+func (r *RouteInfo) topicsOfBroker(brokerName string) []string {
+	topics := make([]string, 0)
+	for topic, queueDataList := range r.topicQueueTable {
+		for _, qd := range queueDataList {
+			if qd.BrokerName == brokerName {
+				topics = append(topics, topic)
+				break
+			}
+		}
+	}
+	return topics
+}
+
 func (r *RouteInfo) deleteTopic(topic string) {
 	r.rw.Lock()
 	defer r.rw.Unlock()
@@ -44,24 +98,53 @@ func (r *RouteInfo) deleteTopic(topic string) {
 	delete(r.topicQueueTable, topic)
 }
 
-func (r *RouteInfo) ScanNotActiveBroker() {
+// ScanNotActiveBroker removes the brokers not updated within the broker
+// expired time and returns how many it removed.
+func (r *RouteInfo) ScanNotActiveBroker() int {
 	Log.Info("scanNotActiveBroker")
 	r.rw.Lock()
 	defer r.rw.Unlock()
 
+	expired := 0
+	now := clock.Mills(r.clock)
 	for addr, info := range r.brokerLiveTable {
 		last := info.GetLastUpdateTime()
-		now := common.CurrentTimeMills()
-		if last + BrokerExpiredTime < now {
+		if info.IsExpired(now, r.brokerExpiredTime) {
 			Log.Warn("broker expired",
 				zap.String("brokerAddr", addr),
 				zap.Int64("lastUpdateTime", last),
 				zap.Int64("currentTime", now))
 			r.removeBroker(addr)
+			expired++
 		} else {
 			Log.Debug("broker live", zap.String("brokerAddr", addr))
 		}
 	}
+	return expired
+}
+
+// Counts returns the number of live brokers, clusters and topics.
+func (r *RouteInfo) Counts() (brokers int, clusters int, topics int) {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	return len(r.brokerLiveTable), len(r.clusterAddrTable), len(r.topicQueueTable)
+}
+
+// OnChannelDestroy removes the brokers registered on the connection from
+// channelAddr right away instead of waiting for them to expire.
+func (r *RouteInfo) OnChannelDestroy(channelAddr string) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	for addr, info := range r.brokerLiveTable {
+		if info.GetChannelAddr() == channelAddr || addr == channelAddr {
+			Log.Info("the broker's channel destroyed, remove it",
+				zap.String("brokerAddr", addr),
+				zap.String("channelAddr", channelAddr))
+			r.removeBroker(addr)
+		}
+	}
 }
 
 func (r *RouteInfo) removeBroker(brokerAddr string) {
@@ -103,6 +186,7 @@ func (r *RouteInfo) removeBroker(brokerAddr string) {
 					zap.String("brokerName", brokerNameFound),
 					zap.String("clusterName", clusterName))
 				if len(brokerNames) == 0 {
+					delete(r.clusterAddrTable, clusterName)
 					Log.Info("remove clusterName from clusterAddrTable",
 						zap.String("clusterName", clusterName))
 				}
@@ -110,9 +194,11 @@ func (r *RouteInfo) removeBroker(brokerAddr string) {
 		}
 	}
 
+	changedTopics := r.topicsOfBroker(brokerNameFound)
 	if removeBrokerName {
-
+		r.removeTopicByBrokerName(brokerNameFound)
 	}
+	r.topicRouteChanged(changedTopics)
 }
 
 func (r *RouteInfo) RegisterBroker(
@@ -121,7 +207,9 @@ func (r *RouteInfo) RegisterBroker(
 	brokerName string,
 	brokerId int64,
 	haServerAddr string,
-	filterServerList *[]string) (string, string) {
+	topicConfigWrapper *pb.TopicConfigSerializeWrapper,
+	filterServerList *[]string,
+	channelAddr string) (string, string) {
 
 	r.rw.Lock()
 	defer r.rw.Unlock()
@@ -153,7 +241,25 @@ func (r *RouteInfo) RegisterBroker(
 	brokerData.BrokerAddrs[brokerId] = brokerAddr
 	registerFirst = registerFirst || (ok == false)
 
-	prevBrokerLiveInfo := NewBrokerLiveInfo(common.CurrentTimeMills(), haServerAddr)
+	changedTopics := make([]string, 0)
+	dataVersion := common.NewDataVersionFromProto(topicConfigWrapper.GetDataVersion())
+	if topicConfigWrapper != nil && brokerId == 0 {
+		if registerFirst || r.isBrokerTopicConfigChanged(brokerAddr, dataVersion) {
+			for _, topicConfig := range topicConfigWrapper.TopicConfigTable {
+				if r.createAndUpdateQueueData(brokerName, topicConfig) {
+					changedTopics = append(changedTopics, topicConfig.TopicName)
+				}
+			}
+		}
+	}
+
+	// A new address changes the broker data of every topic the broker hosts
+	if registerFirst {
+		changedTopics = r.topicsOfBroker(brokerName)
+	}
+	r.topicRouteChanged(changedTopics)
+
+	prevBrokerLiveInfo := NewBrokerLiveInfo(clock.Mills(r.clock), dataVersion, haServerAddr, channelAddr)
 	r.brokerLiveTable[brokerAddr] = *prevBrokerLiveInfo
 	Log.Info("new broker registered",
 		zap.String("brokerAddr", brokerAddr),
@@ -176,6 +282,45 @@ func (r *RouteInfo) RegisterBroker(
 	return "", ""
 }
 
+// IsBrokerTopicConfigChanged reports whether dataVersion differs from the
+// version the broker registered with last time.
+func (r *RouteInfo) IsBrokerTopicConfigChanged(brokerAddr string, dataVersion *common.DataVersion) bool {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	return r.isBrokerTopicConfigChanged(brokerAddr, dataVersion)
+}
+
+func (r *RouteInfo) isBrokerTopicConfigChanged(brokerAddr string, dataVersion *common.DataVersion) bool {
+	prev, ok := r.brokerLiveTable[brokerAddr]
+	if !ok || prev.GetDataVersion() == nil {
+		return true
+	}
+	return !prev.GetDataVersion().Equals(dataVersion)
+}
+
+func (r *RouteInfo) QueryBrokerTopicConfig(brokerAddr string) *common.DataVersion {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	prev, ok := r.brokerLiveTable[brokerAddr]
+	if ok {
+		return prev.GetDataVersion()
+	}
+	return nil
+}
+
+func (r *RouteInfo) UpdateBrokerInfoUpdateTimestamp(brokerAddr string) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	prev, ok := r.brokerLiveTable[brokerAddr]
+	if ok {
+		prev.SetLastUpdateTime(clock.Mills(r.clock))
+		r.brokerLiveTable[brokerAddr] = prev
+	}
+}
+
 func (r *RouteInfo) UnRegisterBroker(clusterName string, brokerAddr string, brokerName string, brokerId int64) {
 	r.rw.Lock()
 	defer r.rw.Unlock()
@@ -193,10 +338,13 @@ func (r *RouteInfo) UnRegisterBroker(clusterName string, brokerAddr string, brok
 	delete(r.filterServerTable, brokerAddr)
 
 	removeBrokerName := false
+	changedTopics := make([]string, 0)
 	brokerData, ok := r.brokerAddrTable[brokerName]
 	if ok {
 		_, ok = brokerData.BrokerAddrs[brokerId]
-		fmt.Println(len(brokerData.BrokerAddrs))
+		if ok {
+			changedTopics = r.topicsOfBroker(brokerName)
+		}
 		if ok {
 			delete(brokerData.BrokerAddrs, brokerId)
 			Log.Info("unregisterBroker, remove from BrokerAddrs OK",
@@ -212,8 +360,8 @@ func (r *RouteInfo) UnRegisterBroker(clusterName string, brokerAddr string, brok
 			delete(r.brokerAddrTable, brokerName)
 			Log.Info("unregisterBroker, remove name from brokerAddrTable OK",
 				zap.String("brokerName", brokerName))
+			removeBrokerName = true
 		}
-		removeBrokerName = true
 	}
 
 	if removeBrokerName {
@@ -234,7 +382,72 @@ func (r *RouteInfo) UnRegisterBroker(clusterName string, brokerAddr string, brok
 				Log.Info("unregisterBroker, remove cluster from clusterAddrTable",
 					zap.String("clusterName", clusterName))
 			}
-			//this.removeTopicByBrokerName(brokerName);
+		}
+		r.removeTopicByBrokerName(brokerName)
+	}
+	r.topicRouteChanged(changedTopics)
+}
+
+// createAndUpdateQueueData reports whether the queue data of the topic changed
+func (r *RouteInfo) createAndUpdateQueueData(brokerName string, topicConfig *pb.TopicConfig) bool {
+	queueData := QueueData{
+		BrokerName:     brokerName,
+		ReadQueueNums:  int(topicConfig.ReadQueueNums),
+		WriteQueueNums: int(topicConfig.WriteQueueNums),
+		Perm:           int(topicConfig.Perm),
+		TopicSysFlag:   int(topicConfig.TopicSysFlag),
+	}
+
+	topic := topicConfig.TopicName
+	queueDataList, ok := r.topicQueueTable[topic]
+	if !ok {
+		r.topicQueueTable[topic] = []QueueData{queueData}
+		Log.Info("new topic registered",
+			zap.String("topic", topic),
+			zap.String("brokerName", brokerName))
+		return true
+	}
+
+	addNewOne := true
+	for i := 0; i < len(queueDataList); i++ {
+		if queueDataList[i].BrokerName != brokerName {
+			continue
+		}
+		if queueDataList[i] == queueData {
+			addNewOne = false
+		} else {
+			Log.Info("topic changed",
+				zap.String("topic", topic),
+				zap.String("brokerName", brokerName))
+			queueDataList = append(queueDataList[:i], queueDataList[i+1:]...)
+			i--
+		}
+	}
+
+	if addNewOne {
+		queueDataList = append(queueDataList, queueData)
+	}
+	r.topicQueueTable[topic] = queueDataList
+	return addNewOne
+}
+
+func (r *RouteInfo) removeTopicByBrokerName(brokerName string) {
+	for topic, queueDataList := range r.topicQueueTable {
+		for i := 0; i < len(queueDataList); i++ {
+			if queueDataList[i].BrokerName == brokerName {
+				Log.Info("remove topic from broker",
+					zap.String("brokerName", brokerName),
+					zap.String("topic", topic))
+				queueDataList = append(queueDataList[:i], queueDataList[i+1:]...)
+				i--
+			}
+		}
+
+		if len(queueDataList) == 0 {
+			Log.Info("remove topic, all queue removed", zap.String("topic", topic))
+			delete(r.topicQueueTable, topic)
+		} else {
+			r.topicQueueTable[topic] = queueDataList
 		}
 	}
 }
@@ -243,19 +456,18 @@ func (r *RouteInfo) PickupTopicRouteData(topic string) *TopicRouteData {
 	foundQueueData := false
 	foundBrokerData := false
 	brokerNameSet := make(map[string]bool)
-	brokerDataList := make([]BrokerData, 0)
-	filterServerMap := make(map[string][]string)
-	topicRouteData := TopicRouteData{}
-
-	topicRouteData.SetBrokerDataList(&brokerDataList)
-	topicRouteData.SetFilterServerTable(&filterServerMap)
+	topicRouteData := TopicRouteData{
+		BrokerDatas:       make([]BrokerData, 0),
+		FilterServerTable: make(map[string][]string),
+	}
 
 	r.rw.RLock()
 	defer r.rw.RUnlock()
 
+	// Copy everything handed out, the tables keep changing once unlocked
 	queueDataList, ok := r.topicQueueTable[topic]
 	if ok {
-		topicRouteData.SetQueueDataList(&queueDataList)
+		topicRouteData.QueueDatas = append([]QueueData(nil), queueDataList...)
 		foundQueueData = true
 
 		for _, qd := range queueDataList {
@@ -265,19 +477,28 @@ func (r *RouteInfo) PickupTopicRouteData(topic string) *TopicRouteData {
 		for brokerName := range brokerNameSet {
 			brokerData, ok := r.brokerAddrTable[brokerName]
 			if ok {
-				brokerDataClone := BrokerData{Cluster: brokerData.Cluster, BrokerName: brokerData.BrokerName, BrokerAddrs: brokerData.BrokerAddrs}
-				brokerDataList = append(brokerDataList, brokerDataClone)
+				brokerDataClone := BrokerData{
+					Cluster:     brokerData.Cluster,
+					BrokerName:  brokerData.BrokerName,
+					BrokerAddrs: make(map[int64]string, len(brokerData.BrokerAddrs)),
+				}
+				for id, addr := range brokerData.BrokerAddrs {
+					brokerDataClone.BrokerAddrs[id] = addr
+				}
+				topicRouteData.BrokerDatas = append(topicRouteData.BrokerDatas, brokerDataClone)
 				foundBrokerData = true
 
 				for _, brokerAddr := range brokerDataClone.BrokerAddrs {
-					filterServerList := r.filterServerTable[brokerAddr]
-					filterServerMap[brokerAddr] = filterServerList
+					filterServerList, ok := r.filterServerTable[brokerAddr]
+					if ok {
+						topicRouteData.FilterServerTable[brokerAddr] = filterServerList
+					}
 				}
 			}
 		}
 	}
 
-	Log.Sugar().Debug("PickupTopicRouteData topic: %s, topicRouteData: %v", topic, topicRouteData)
+	Log.Sugar().Debugf("PickupTopicRouteData topic: %s, topicRouteData: %v", topic, topicRouteData)
 
 	if foundQueueData && foundBrokerData {
 		return &topicRouteData
@@ -295,8 +516,7 @@ func (r *RouteInfo) GetAllClusterInfo() []byte {
 		ClusterAddrTable: r.clusterAddrTable,
 	}
 
-	data, _ := json.Marshal(c)
-	return data
+	return c.Encode()
 }
 
 func (r *RouteInfo) GetAllTopicList() []byte {
@@ -317,12 +537,60 @@ func (r *RouteInfo) DeleteTopic(topic string) {
 	r.rw.Lock()
 	defer r.rw.Unlock()
 
-	delete(r.topicQueueTable, topic)
+	if _, ok := r.topicQueueTable[topic]; ok {
+		delete(r.topicQueueTable, topic)
+		r.topicRouteChanged([]string{topic})
+	}
+}
+
+// WipeWritePermOfBroker clears the write permission of every queue hosted by
+// brokerName and returns the number of topics touched.
+func (r *RouteInfo) WipeWritePermOfBroker(brokerName string) int {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	return r.operateWritePermOfBroker(brokerName, true)
+}
+
+// AddWritePermOfBroker restores the write permission wiped by
+// WipeWritePermOfBroker and returns the number of topics touched.
+func (r *RouteInfo) AddWritePermOfBroker(brokerName string) int {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	return r.operateWritePermOfBroker(brokerName, false)
+}
+
+func (r *RouteInfo) operateWritePermOfBroker(brokerName string, wipe bool) int {
+	topicCnt := 0
+	changedTopics := make([]string, 0)
+	for topic, queueDataList := range r.topicQueueTable {
+		for i := range queueDataList {
+			if queueDataList[i].BrokerName != brokerName {
+				continue
+			}
+
+			p := queueDataList[i].Perm
+			if wipe {
+				p &= ^perm.PermWrite
+			} else {
+				p |= perm.PermWrite
+			}
+			if queueDataList[i].Perm != p {
+				queueDataList[i].Perm = p
+				changedTopics = append(changedTopics, topic)
+			}
+			topicCnt++
+		}
+	}
+
+	r.topicRouteChanged(changedTopics)
+	return topicCnt
 }
 
 func (r *RouteInfo) GetTopicByCluster(cluster string) []byte {
 	r.rw.RLock()
-	r.rw.Unlock()
+	defer r.rw.RUnlock()
 
 	topicList := TopicList{TopicList: make(map[string]bool)}
 	brokerSet, _ := r.clusterAddrTable[cluster]
@@ -348,7 +616,7 @@ func (r *RouteInfo) GetTopicByCluster(cluster string) []byte {
 
 func (r *RouteInfo) GetSystemTopicList() []byte {
 	r.rw.RLock()
-	defer r.rw.Unlock()
+	defer r.rw.RUnlock()
 
 	topicSet := make(map[string]bool)
 	topicList := TopicList{
@@ -379,7 +647,7 @@ func (r *RouteInfo) GetSystemTopicList() []byte {
 
 func (r *RouteInfo) GetUnitTopicList() []byte {
 	r.rw.RLock()
-	defer r.rw.Unlock()
+	defer r.rw.RUnlock()
 
 	topicSet := make(map[string]bool)
 	topicList := TopicList{
@@ -402,7 +670,7 @@ func (r *RouteInfo) GetUnitTopicList() []byte {
 
 func (r *RouteInfo) GetHasUnitSubTopicList() []byte {
 	r.rw.RLock()
-	defer r.rw.Unlock()
+	defer r.rw.RUnlock()
 
 	topicSet := make(map[string]bool)
 	topicList := TopicList{
@@ -425,7 +693,7 @@ func (r *RouteInfo) GetHasUnitSubTopicList() []byte {
 
 func (r *RouteInfo) GetHasUnitSubUnUnitTopicList() []byte {
 	r.rw.RLock()
-	defer r.rw.Unlock()
+	defer r.rw.RUnlock()
 
 	topicSet := make(map[string]bool)
 	topicList := TopicList{
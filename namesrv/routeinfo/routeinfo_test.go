@@ -0,0 +1,243 @@
+package routeinfo
+
+import (
+	"rocketmq-go/common/clock"
+	"rocketmq-go/common/perm"
+	pb "rocketmq-go/common/proto"
+	. "rocketmq-go/common/proto/route"
+	"sync"
+	"testing"
+	"time"
+)
+
+func topicConfigWrapper(topics ...string) *pb.TopicConfigSerializeWrapper {
+	table := make(map[string]*pb.TopicConfig)
+	for _, topic := range topics {
+		table[topic] = &pb.TopicConfig{
+			TopicName:      topic,
+			ReadQueueNums:  4,
+			WriteQueueNums: 4,
+			Perm:           perm.PermRead | perm.PermWrite,
+		}
+	}
+	return &pb.TopicConfigSerializeWrapper{
+		TopicConfigTable: table,
+		DataVersion:      &pb.DataVersion{Timestamp: 1, Counter: 1},
+	}
+}
+
+func TestRegisterBrokerCreatesRoute(t *testing.T) {
+	r := NewRouteInfo()
+	r.RegisterBroker("cluster", "127.0.0.1:10911", "broker-a", 0, "",
+		topicConfigWrapper("TopicA"), nil, "127.0.0.1:50000")
+
+	if r.PickupTopicRouteData("TopicA") == nil {
+		t.Fatal("no route for registered topic")
+	}
+	if r.PickupTopicRouteData("TopicB") != nil {
+		t.Fatal("route for unknown topic")
+	}
+}
+
+func TestOnChannelDestroyRemovesBroker(t *testing.T) {
+	r := NewRouteInfo()
+	r.RegisterBroker("cluster", "127.0.0.1:10911", "broker-a", 0, "",
+		topicConfigWrapper("TopicA"), nil, "127.0.0.1:50000")
+	r.RegisterBroker("cluster", "127.0.0.1:10921", "broker-b", 0, "",
+		topicConfigWrapper("TopicB"), nil, "127.0.0.1:50001")
+
+	r.OnChannelDestroy("127.0.0.1:50000")
+
+	if r.PickupTopicRouteData("TopicA") != nil {
+		t.Fatal("route of broker-a should be removed with its channel")
+	}
+	if r.PickupTopicRouteData("TopicB") == nil {
+		t.Fatal("route of broker-b should be kept")
+	}
+}
+
+func TestRemoveBrokerDropsEmptyCluster(t *testing.T) {
+	r := NewRouteInfo()
+	r.RegisterBroker("cluster-a", "127.0.0.1:10911", "broker-a", 0, "",
+		topicConfigWrapper("TopicA"), nil, "127.0.0.1:50000")
+	r.RegisterBroker("cluster-b", "127.0.0.1:10921", "broker-b", 0, "",
+		topicConfigWrapper("TopicB"), nil, "127.0.0.1:50001")
+
+	r.OnChannelDestroy("127.0.0.1:50000")
+
+	if brokers, clusters, topics := r.Counts(); brokers != 1 || clusters != 1 || topics != 1 {
+		t.Fatalf("counts %d brokers, %d clusters, %d topics, want 1 of each", brokers, clusters, topics)
+	}
+}
+
+func TestScanNotActiveBrokerExpires(t *testing.T) {
+	fake := clock.NewFake(time.Unix(1600000000, 0))
+	r := NewRouteInfo()
+	r.SetClock(fake)
+	r.SetBrokerExpiredTime(2000)
+	r.RegisterBroker("cluster", "127.0.0.1:10911", "broker-a", 0, "",
+		topicConfigWrapper("TopicA"), nil, "127.0.0.1:50000")
+	r.RegisterBroker("cluster", "127.0.0.1:10921", "broker-b", 0, "",
+		topicConfigWrapper("TopicB"), nil, "127.0.0.1:50001")
+
+	fake.Advance(1500 * time.Millisecond)
+	r.UpdateBrokerInfoUpdateTimestamp("127.0.0.1:10921")
+	fake.Advance(500 * time.Millisecond)
+	if expired := r.ScanNotActiveBroker(); expired != 0 {
+		t.Fatalf("expired %d brokers at the expired time", expired)
+	}
+
+	fake.Advance(time.Millisecond)
+	if expired := r.ScanNotActiveBroker(); expired != 1 {
+		t.Fatalf("expired %d brokers, want broker-a", expired)
+	}
+	if r.PickupTopicRouteData("TopicA") != nil || r.PickupTopicRouteData("TopicB") == nil {
+		t.Fatal("broker-b updated later should outlive broker-a")
+	}
+
+	fake.Advance(1500 * time.Millisecond)
+	if expired := r.ScanNotActiveBroker(); expired != 1 {
+		t.Fatalf("expired %d brokers, want broker-b", expired)
+	}
+}
+
+// permOf returns the perm of the queues of brokerName in the route of topic,
+// -1 when it has none.
+func permOf(r *RouteInfo, topic string, brokerName string) int {
+	routeData := r.PickupTopicRouteData(topic)
+	if routeData == nil {
+		return -1
+	}
+	for _, queueData := range routeData.QueueDatas {
+		if queueData.BrokerName == brokerName {
+			return queueData.Perm
+		}
+	}
+	return -1
+}
+
+func TestWritePermOfBroker(t *testing.T) {
+	r := NewRouteInfo()
+	r.RegisterBroker("cluster", "127.0.0.1:10911", "broker-a", 0, "",
+		topicConfigWrapper("TopicA", "TopicB"), nil, "127.0.0.1:50000")
+	r.RegisterBroker("cluster", "127.0.0.1:10921", "broker-b", 0, "",
+		topicConfigWrapper("TopicA"), nil, "127.0.0.1:50001")
+	readWrite := perm.PermRead | perm.PermWrite
+
+	if count := r.WipeWritePermOfBroker("broker-a"); count != 2 {
+		t.Fatalf("wipe count = %d, want 2", count)
+	}
+	if permOf(r, "TopicA", "broker-a") != perm.PermRead || permOf(r, "TopicB", "broker-a") != perm.PermRead {
+		t.Fatal("write perm of broker-a kept")
+	}
+	if permOf(r, "TopicA", "broker-b") != readWrite {
+		t.Fatal("write perm of broker-b wiped")
+	}
+	// The topics of the broker are counted, changed or not
+	if count := r.WipeWritePermOfBroker("broker-a"); count != 2 {
+		t.Fatalf("wipe again count = %d, want 2", count)
+	}
+
+	if count := r.AddWritePermOfBroker("broker-a"); count != 2 {
+		t.Fatalf("add count = %d, want 2", count)
+	}
+	if permOf(r, "TopicA", "broker-a") != readWrite || permOf(r, "TopicB", "broker-a") != readWrite {
+		t.Fatal("write perm of broker-a not restored")
+	}
+
+	if count := r.WipeWritePermOfBroker("broker-c"); count != 0 {
+		t.Fatalf("wipe count of unknown broker = %d", count)
+	}
+	if count := r.AddWritePermOfBroker("broker-c"); count != 0 {
+		t.Fatalf("add count of unknown broker = %d", count)
+	}
+}
+
+func TestRegisterBrokerQueueData(t *testing.T) {
+	r := NewRouteInfo()
+	register := func(brokerId int64, brokerAddr string, counter int64, topicConfig *pb.TopicConfig) {
+		r.RegisterBroker("cluster", brokerAddr, "broker-a", brokerId, "",
+			&pb.TopicConfigSerializeWrapper{
+				TopicConfigTable: map[string]*pb.TopicConfig{topicConfig.TopicName: topicConfig},
+				DataVersion:      &pb.DataVersion{Timestamp: 1, Counter: counter},
+			}, nil, "127.0.0.1:50000")
+	}
+	queueDataOf := func(topic string) []QueueData {
+		routeData := r.PickupTopicRouteData(topic)
+		if routeData == nil {
+			return nil
+		}
+		return routeData.QueueDatas
+	}
+
+	register(0, "127.0.0.1:10911", 1, &pb.TopicConfig{
+		TopicName: "TopicA", ReadQueueNums: 8, WriteQueueNums: 4, Perm: perm.PermRead, TopicSysFlag: 1})
+	want := QueueData{BrokerName: "broker-a", ReadQueueNums: 8, WriteQueueNums: 4, Perm: perm.PermRead, TopicSysFlag: 1}
+	if queueDatas := queueDataOf("TopicA"); len(queueDatas) != 1 || queueDatas[0] != want {
+		t.Fatalf("queue data = %+v, want %+v", queueDatas, want)
+	}
+
+	// The same data version keeps the registered topic config
+	register(0, "127.0.0.1:10911", 1, &pb.TopicConfig{TopicName: "TopicA", ReadQueueNums: 16, WriteQueueNums: 16})
+	if queueDatas := queueDataOf("TopicA"); len(queueDatas) != 1 || queueDatas[0] != want {
+		t.Fatalf("queue data of the same version = %+v, want %+v", queueDatas, want)
+	}
+
+	register(0, "127.0.0.1:10911", 2, &pb.TopicConfig{
+		TopicName: "TopicA", ReadQueueNums: 16, WriteQueueNums: 16, Perm: perm.PermRead | perm.PermWrite})
+	want = QueueData{BrokerName: "broker-a", ReadQueueNums: 16, WriteQueueNums: 16, Perm: perm.PermRead | perm.PermWrite}
+	if queueDatas := queueDataOf("TopicA"); len(queueDatas) != 1 || queueDatas[0] != want {
+		t.Fatalf("queue data of the next version = %+v, want %+v", queueDatas, want)
+	}
+
+	// Only masters register their topics
+	register(1, "127.0.0.1:10912", 1, &pb.TopicConfig{TopicName: "TopicB", ReadQueueNums: 4, WriteQueueNums: 4})
+	if queueDatas := queueDataOf("TopicB"); queueDatas != nil {
+		t.Fatalf("queue data registered by a slave = %+v", queueDatas)
+	}
+}
+
+func TestReadPathsReleaseLock(t *testing.T) {
+	reads := map[string]func(r *RouteInfo){
+		"Counts":                       func(r *RouteInfo) { r.Counts() },
+		"IsBrokerTopicConfigChanged":   func(r *RouteInfo) { r.IsBrokerTopicConfigChanged("127.0.0.1:10911", nil) },
+		"QueryBrokerTopicConfig":       func(r *RouteInfo) { r.QueryBrokerTopicConfig("127.0.0.1:10911") },
+		"PickupTopicRouteData":         func(r *RouteInfo) { r.PickupTopicRouteData("TopicA") },
+		"GetAllClusterInfo":            func(r *RouteInfo) { r.GetAllClusterInfo() },
+		"GetAllTopicList":              func(r *RouteInfo) { r.GetAllTopicList() },
+		"GetTopicByCluster":            func(r *RouteInfo) { r.GetTopicByCluster("cluster") },
+		"GetSystemTopicList":           func(r *RouteInfo) { r.GetSystemTopicList() },
+		"GetUnitTopicList":             func(r *RouteInfo) { r.GetUnitTopicList() },
+		"GetHasUnitSubTopicList":       func(r *RouteInfo) { r.GetHasUnitSubTopicList() },
+		"GetHasUnitSubUnUnitTopicList": func(r *RouteInfo) { r.GetHasUnitSubUnUnitTopicList() },
+	}
+
+	// A read path keeping its lock blocks the write after it, one taking
+	// the read lock but releasing the write lock is a fatal error
+	for name, read := range reads {
+		r := NewRouteInfo()
+		r.RegisterBroker("cluster", "127.0.0.1:10911", "broker-a", 0, "",
+			topicConfigWrapper("TopicA"), nil, "127.0.0.1:50000")
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			var wg sync.WaitGroup
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 100; j++ {
+						read(r)
+					}
+				}()
+			}
+			wg.Wait()
+			r.UpdateBrokerInfoUpdateTimestamp("127.0.0.1:10911")
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s keeps the lock, a write after it blocks", name)
+		}
+	}
+}
@@ -1,68 +1,118 @@
 package namesrv
 
-import "time"
+import (
+	"rocketmq-go/common/clock"
+	"sync"
+	"time"
+)
 
 type Event func()
 
+// Scheduler runs events periodically, timers added before Start wait for
+// it, those added after it start right away.
 type Scheduler struct {
-	timers map[int]timer
+	mu sync.Mutex
+	clock clock.Clock
+	started bool
+	timers map[int]*timer
 }
 
 type timer struct {
-	timer *time.Ticker
-	stopChan chan bool
+	period time.Duration
 	event Event
+	stopChan chan struct{}
+	done chan struct{}
 }
 
 func NewScheduler() *Scheduler {
 	return &Scheduler{
-		make(map[int]timer),
+		clock: clock.Real,
+		timers: make(map[int]*timer),
 	}
 }
 
+// SetClock changes the clock the timers tick on, it must be called before
+// Start.
+func (t *Scheduler) SetClock(c clock.Clock) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.clock = c
+}
+
 func (t *Scheduler) Add(id int, period time.Duration, event Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	_, ok := t.timers[id]
 	if ok {
 		return
 	}
-	timer := timer{
-		time.NewTicker(period),
-		make(chan bool),
-		event,
+	timer := &timer{
+		period: period,
+		event: event,
 	}
 	t.timers[id] = timer
-	timer.start()
+	if t.started {
+		timer.start(t.clock)
+	}
 }
 
 func (t *Scheduler) Del(id int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	timer, ok := t.timers[id]
 	if !ok {
 		return
 	}
-	timer.stop()
+	if t.started {
+		timer.stop()
+	}
 	delete(t.timers, id)
 }
 
 func (t *Scheduler) Start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.started {
+		return
+	}
+	t.started = true
 	for _, timer := range t.timers {
-		timer.start()
+		timer.start(t.clock)
 	}
 }
 
+// Stop stops every timer and waits for the events running to return.
 func (t *Scheduler) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.started {
+		return
+	}
+	t.started = false
 	for _, timer := range t.timers {
 		timer.stop()
 	}
 }
 
-func (t *timer) start() {
+// start creates the ticker before it returns, so a fake clock advanced
+// after it fires the ticker.
+func (t *timer) start(c clock.Clock) {
+	t.stopChan = make(chan struct{})
+	t.done = make(chan struct{})
+	ticker := c.NewTicker(t.period)
 	go func() {
+		defer close(t.done)
+		defer ticker.Stop()
 		for {
 			select {
-			case <- t.timer.C:
+			case <- ticker.C():
 				t.event()
 			case <- t.stopChan:
-				t.timer.Stop()
 				return
 			}
 		}
@@ -70,6 +120,6 @@ func (t *timer) start() {
 }
 
 func (t *timer) stop() {
-	t.stopChan <- true
 	close(t.stopChan)
-}
\ No newline at end of file
+	<- t.done
+}
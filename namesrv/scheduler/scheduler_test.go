@@ -0,0 +1,84 @@
+package namesrv
+
+import (
+	"rocketmq-go/common/clock"
+	"testing"
+	"time"
+)
+
+func TestStopWaitsForRunningEvent(t *testing.T) {
+	running := make(chan struct{})
+	finished := make(chan struct{})
+
+	s := NewScheduler()
+	s.Add(0, 10*time.Millisecond, func() {
+		select {
+		case running <- struct{}{}:
+			time.Sleep(50 * time.Millisecond)
+			close(finished)
+		default:
+		}
+	})
+	s.Start()
+	<-running
+	s.Stop()
+
+	select {
+	case <-finished:
+	default:
+		t.Fatal("Stop returned before the running event")
+	}
+}
+
+func TestAddAfterStart(t *testing.T) {
+	runs := make(chan struct{}, 100)
+
+	s := NewScheduler()
+	s.Start()
+	s.Add(0, 10*time.Millisecond, func() { runs <- struct{}{} })
+	defer s.Stop()
+
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Fatal("event added after Start never ran")
+	}
+}
+
+func TestEventsRunOnTheClock(t *testing.T) {
+	runs := make(chan int, 100)
+	fake := clock.NewFake(time.Unix(1600000000, 0))
+
+	s := NewScheduler()
+	s.SetClock(fake)
+	s.Add(0, 10*time.Second, func() { runs <- 0 })
+	s.Add(1, time.Minute, func() { runs <- 1 })
+	s.Start()
+	defer s.Stop()
+
+	next := func() int {
+		select {
+		case id := <-runs:
+			return id
+		case <-time.After(time.Second):
+			t.Fatal("no event ran")
+			return -1
+		}
+	}
+
+	fake.Advance(9 * time.Second)
+	fake.Advance(time.Second)
+	if id := next(); id != 0 {
+		t.Fatalf("event %d ran at 10s", id)
+	}
+	s.Del(0)
+	fake.Advance(50 * time.Second)
+	if id := next(); id != 1 {
+		t.Fatalf("event %d ran at 1m", id)
+	}
+	select {
+	case id := <-runs:
+		t.Fatalf("event %d ran again", id)
+	default:
+	}
+}
@@ -0,0 +1,222 @@
+// Package namesrv runs a name server in process, the namesrv command is a
+// thin wrapper of it reading the config file and the signals.
+package namesrv
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"net"
+	"path/filepath"
+	"rocketmq-go/acl"
+	pb "rocketmq-go/common/proto"
+	. "rocketmq-go/logging"
+	"rocketmq-go/metrics"
+	"rocketmq-go/namesrv/admin"
+	"rocketmq-go/namesrv/config"
+	"rocketmq-go/namesrv/control"
+	"rocketmq-go/namesrv/processor"
+	"rocketmq-go/remote"
+	"rocketmq-go/remote/native"
+	"sync"
+	"time"
+)
+
+// Server is a name server with its listeners. Listen addresses with port 0
+// pick a free port, see Addr.
+type Server struct {
+	// Listener serves the gRPC API instead of listening on ListenAddr when
+	// set before Start, e.g. an in-memory listener.
+	Listener net.Listener
+
+	ctl *control.Control
+
+	mu         sync.Mutex
+	started    bool
+	running    bool
+	remoteSrv  *remote.Server
+	nativeSrv  *native.Server
+	adminSrv   *admin.Server
+	metricsSrv *metrics.Server
+}
+
+// NewServer creates a name server running with conf, which the server owns
+// from then on. config.Load reads conf the way the namesrv command does,
+// config.Default is a base to fill in code.
+func NewServer(conf *config.Config) (*Server, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	return &Server{ctl: control.NewControl(conf)}, nil
+}
+
+// Control returns the state of the server, e.g. its route info.
+func (s *Server) Control() *control.Control {
+	return s.ctl
+}
+
+// Start listens on the addresses of the config and serves in the
+// background. A server is started once, a Start which fails leaves nothing
+// running and may be tried again.
+func (s *Server) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return errors.New("namesrv: server already started")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	conf := s.ctl.NameSrvConf.Snapshot()
+	remoteSrv := remote.NewServer(conf.ListenAddr)
+	remoteSrv.Listener = s.Listener
+	remoteSrv.Use(s.ctl.Metrics.Interceptor(), remote.Recovery(), remote.AccessLog())
+	if conf.ACLEnable {
+		validator, err := acl.NewPlainAccessValidator(conf.ACLConfigPath)
+		if err != nil {
+			return err
+		}
+		auditLogPath := conf.ACLAuditLogPath
+		if auditLogPath == "" {
+			auditLogPath = filepath.Join(conf.RocketmqHome, "log", "acl_audit.log")
+		}
+		InitAudit(auditLogPath)
+		remoteSrv.Use(validator.Interceptor())
+	}
+	remoteSrv.Use(remote.Validation(), native.Translate())
+
+	defaultProcessor := processor.NewDefaultProcessor(s.ctl)
+	defaultExecutor := remote.NewExecutor("DefaultExecutor", conf.DefaultThreadPoolNums, conf.DefaultThreadPoolQueueCapacity)
+	remoteSrv.RegisterDefaultProcessor(defaultProcessor.Process, defaultExecutor)
+	// Keep broker registrations from queueing behind client route lookups
+	brokerExecutor := remote.NewExecutor("BrokerExecutor", conf.BrokerThreadPoolNums, conf.BrokerThreadPoolQueueCapacity)
+	for _, code := range []pb.RequestCode{
+		pb.RequestCode_REGISTER_BROKER,
+		pb.RequestCode_UNREGISTER_BROKER,
+		pb.RequestCode_QUERY_DATA_VERSION,
+	} {
+		remoteSrv.RegisterProcessor(code, defaultProcessor.Process, brokerExecutor)
+	}
+	remoteSrv.ChannelEventListener = s.ctl.BrokerHousekeepingService
+
+	if conf.TLSEnable {
+		remoteSrv.TLS = &remote.TLSConfig{
+			CertFile:        conf.TLSCertPath,
+			KeyFile:         conf.TLSKeyPath,
+			CAFile:          conf.TLSCAPath,
+			ClientAuth:      conf.TLSNeedClientAuth,
+			AllowedSubjects: conf.TLSAllowedSubjects,
+			ReloadInterval:  time.Duration(conf.TLSReloadIntervalSeconds) * time.Second,
+		}
+	}
+
+	s.remoteSrv = remoteSrv
+	s.ctl.RemoteSrv = remoteSrv
+	if conf.NativeListenAddr != "" {
+		s.nativeSrv = native.NewServer(conf.NativeListenAddr, remoteSrv)
+		s.ctl.NativeSrv = s.nativeSrv
+	}
+	if conf.AdminListenAddr != "" {
+		s.adminSrv = admin.NewServer(conf.AdminListenAddr, s.ctl)
+		s.ctl.AdminSrv = s.adminSrv
+	}
+	if conf.MetricsListenAddr != "" {
+		s.metricsSrv = metrics.NewServer(conf.MetricsListenAddr, s.ctl.Metrics.Registry)
+		s.ctl.MetricsSrv = s.metricsSrv
+	}
+
+	if err := s.ctl.Start(); err != nil {
+		// Started services shut their executors down when stopped, the
+		// ones never started leave them to us
+		defaultExecutor.Shutdown()
+		brokerExecutor.Shutdown()
+		return err
+	}
+
+	// Registered once started, a failed Start leaves nothing behind
+	if conf.TLSEnable {
+		s.ctl.OnReload(func(conf *config.Config) {
+			if err := remoteSrv.ReloadTLS(conf.TLSCertPath, conf.TLSKeyPath, conf.TLSCAPath); err != nil {
+				Log.Error("Reload tls certificates failed, keep the running ones", zap.Error(err))
+			}
+		})
+	}
+	s.ctl.Metrics.ObserveConnections("grpc", remoteSrv.ActiveConnections)
+	if s.nativeSrv != nil {
+		s.ctl.Metrics.ObserveConnections("native", s.nativeSrv.ActiveConnections)
+	}
+	s.started = true
+	s.running = true
+	return nil
+}
+
+// Reload applies the settings of conf which can change while running, see
+// control.Control.Reload.
+func (s *Server) Reload(conf *config.Config) {
+	s.ctl.Reload(conf)
+}
+
+// Shutdown stops the server letting the requests in flight finish until ctx
+// is done, see control.Control.Shutdown. A server not running returns right
+// away.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	running := s.running
+	s.running = false
+	s.mu.Unlock()
+
+	if !running {
+		return nil
+	}
+	return s.ctl.Shutdown(ctx)
+}
+
+// Addr returns the address of the gRPC listener, nil when the server is not
+// running.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return nil
+	}
+	return s.remoteSrv.Addr()
+}
+
+// NativeAddr returns the address of the java remoting listener, nil when
+// the server is not running or the listener disabled.
+func (s *Server) NativeAddr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running || s.nativeSrv == nil {
+		return nil
+	}
+	return s.nativeSrv.Addr()
+}
+
+// AdminAddr returns the address of the admin API listener, nil when the
+// server is not running or the listener disabled.
+func (s *Server) AdminAddr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running || s.adminSrv == nil {
+		return nil
+	}
+	return s.adminSrv.Addr()
+}
+
+// MetricsAddr returns the address of the metrics listener, nil when the
+// server is not running or the listener disabled.
+func (s *Server) MetricsAddr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running || s.metricsSrv == nil {
+		return nil
+	}
+	return s.metricsSrv.Addr()
+}
@@ -0,0 +1,390 @@
+package namesrv
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"rocketmq-go/common"
+	"rocketmq-go/logging"
+	pb "rocketmq-go/common/proto"
+	"rocketmq-go/namesrv/config"
+	"rocketmq-go/remote"
+	"rocketmq-go/remote/native"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testConfig(t *testing.T) *config.Config {
+	dir, err := ioutil.TempDir("", "namesrv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	conf := config.Default()
+	conf.KVConfigPath = filepath.Join(dir, "kvConfig.json")
+	conf.ConfigStorePath = filepath.Join(dir, "namesrv.properties")
+	conf.ListenAddr = "127.0.0.1:0"
+	return conf
+}
+
+func TestServerLifecycle(t *testing.T) {
+	conf := testConfig(t)
+	conf.AdminListenAddr = "127.0.0.1:0"
+	s, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Addr() != nil {
+		t.Fatalf("addr before start = %v", s.Addr())
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(context.Background()); err == nil {
+		t.Fatal("second start succeeded")
+	}
+	if s.NativeAddr() != nil || s.MetricsAddr() != nil {
+		t.Fatal("disabled listeners have an address")
+	}
+
+	c := remote.NewClient(s.Addr().String())
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	header := &pb.PutKVConfigRequestHeader{Namespace: "ns", Key: "k", Value: "v"}
+	request := &pb.RemoteCommand{Code: int32(pb.RequestCode_PUT_KV_CONFIG), Header: common.Serializable(header)}
+	response, err := c.InvokeSync(context.Background(), request, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Code != int32(pb.ResponseCode_SUCCESS) {
+		t.Fatalf("response code = %v", pb.ResponseCode(response.Code))
+	}
+	if value := s.Control().KVConfig.GetKVConfig("ns", "k"); value != "v" {
+		t.Fatalf("kv config = %q", value)
+	}
+
+	resp, err := http.Get("http://" + s.AdminAddr().String() + "/kv/ns/k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("admin status = %d", resp.StatusCode)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s.Addr() != nil {
+		t.Fatalf("addr after shutdown = %v", s.Addr())
+	}
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("second shutdown: %v", err)
+	}
+}
+
+func TestServerStartFails(t *testing.T) {
+	running, err := NewServer(testConfig(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := running.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer running.Shutdown(context.Background())
+
+	conf := testConfig(t)
+	conf.ListenAddr = running.Addr().String()
+	s, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(context.Background()); err == nil {
+		t.Fatal("start on a taken address succeeded")
+	}
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerStartAgainAfterFailure(t *testing.T) {
+	conf := testConfig(t)
+	conf.DefaultThreadPoolNums = 64
+	conf.MetricsListenAddr = "127.0.0.1:0"
+	if err := ioutil.WriteFile(conf.KVConfigPath, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goroutines := runtime.NumGoroutine()
+	if err := s.Start(context.Background()); err == nil {
+		t.Fatal("start with a broken kv config succeeded")
+	}
+	// The executor workers of the failed start are gone
+	if n := runtime.NumGoroutine(); n >= goroutines+conf.DefaultThreadPoolNums {
+		t.Fatalf("%d goroutines after the failed start, %d before", n, goroutines)
+	}
+	if s.Addr() != nil {
+		t.Fatalf("addr after the failed start = %v", s.Addr())
+	}
+
+	if err := os.Remove(conf.KVConfigPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("start again: %v", err)
+	}
+	defer s.Shutdown(context.Background())
+	if s.Addr() == nil || s.MetricsAddr() == nil {
+		t.Fatal("no address after starting again")
+	}
+	if err := s.Start(context.Background()); err == nil {
+		t.Fatal("start of a running server succeeded")
+	}
+}
+
+func TestNewServerValidates(t *testing.T) {
+	conf := testConfig(t)
+	conf.LogLevel = "loud"
+	if _, err := NewServer(conf); err == nil {
+		t.Fatal("invalid config accepted")
+	}
+}
+
+func TestServerReloadWhileServing(t *testing.T) {
+	conf := testConfig(t)
+	s, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orderOn := conf.Snapshot()
+	orderOn.OrderMessageEnable = true
+	orderOn.LogLevel = "info"
+	orderOff := conf.Snapshot()
+	reloaded := make(chan bool, 1)
+	s.Control().OnReload(func(conf *config.Config) {
+		select {
+		case reloaded <- conf.OrderMessageEnable:
+		default:
+		}
+	})
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown(context.Background())
+	s.Control().RouteInfo.RegisterBroker("cluster", "127.0.0.1:10911", "broker-a", 0, "",
+		&pb.TopicConfigSerializeWrapper{
+			TopicConfigTable: map[string]*pb.TopicConfig{"TopicA": {TopicName: "TopicA", ReadQueueNums: 4, WriteQueueNums: 4}},
+			DataVersion:      &pb.DataVersion{},
+		}, nil, "127.0.0.1:50000")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			if i%2 == 0 {
+				s.Reload(orderOn)
+			} else {
+				s.Reload(orderOff)
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if s.Control().PickupTopicRouteData("TopicA") == nil {
+			t.Fatal("no route of TopicA while reloading")
+		}
+	}
+	<-done
+
+	select {
+	case <-reloaded:
+	default:
+	}
+	s.Reload(orderOn)
+	if order := <-reloaded; !order {
+		t.Fatal("reload listener got the config before the reload")
+	}
+}
+
+func TestServerACLOnNativeListener(t *testing.T) {
+	auditLog := logging.AuditLog
+	t.Cleanup(func() { logging.AuditLog = auditLog })
+
+	conf := testConfig(t)
+	dir := filepath.Dir(conf.KVConfigPath)
+	conf.NativeListenAddr = "127.0.0.1:0"
+	conf.ACLEnable = true
+	conf.ACLConfigPath = filepath.Join(dir, "plain_acl.toml")
+	conf.ACLAuditLogPath = filepath.Join(dir, "acl_audit.log")
+	acl := "[[accounts]]\naccessKey = \"client\"\nsecretKey = \"client-secret\"\nrole = \"readonly\"\n"
+	if err := ioutil.WriteFile(conf.ACLConfigPath, []byte(acl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown(context.Background())
+
+	conn, err := net.Dial("tcp", s.NativeAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	invoke := func(cmd *native.Command) int32 {
+		frame, err := cmd.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.Write(frame); err != nil {
+			t.Fatal(err)
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		response, err := native.ReadCommand(conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return response.Code
+	}
+
+	// GET_ROUTEINFO_BY_TOPIC signed by a java client, see acl.TestValidateJava
+	request := &native.Command{
+		Code:          105,
+		Language:      "JAVA",
+		Opaque:        1,
+		SerializeType: native.SerializeJSON,
+		ExtFields: map[string]string{
+			"AccessKey": "client",
+			"Signature": "dDQVLKANzh9ALoxTEZZ7vFMtCY8=",
+			"topic":     "TopicTest",
+		},
+	}
+	if code := invoke(request); code != 17 {
+		t.Fatalf("java signed request: code = %d, want TOPIC_NOT_EXIST", code)
+	}
+	request.Opaque = 2
+	request.ExtFields["topic"] = "TopicOther"
+	if code := invoke(request); code != 16 {
+		t.Fatalf("tampered java request: code = %d, want NO_PERMISSION", code)
+	}
+}
+
+func TestServerMetricsConnections(t *testing.T) {
+	conf := testConfig(t)
+	conf.NativeListenAddr = "127.0.0.1:0"
+	conf.MetricsListenAddr = "127.0.0.1:0"
+	s, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown(context.Background())
+
+	c := remote.NewClient(s.Addr().String())
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+	request := &pb.RemoteCommand{Code: int32(pb.RequestCode_GET_BROKER_CLUSTER_INFO)}
+	if _, err := c.InvokeSync(context.Background(), request, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// A served request proves the native connection is open
+	conn, err := net.Dial("tcp", s.NativeAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	frame, err := (&native.Command{Code: 106, Opaque: 1, SerializeType: native.SerializeJSON}).Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	if _, err := native.ReadCommand(conn); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get("http://" + s.MetricsAddr().String() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	for _, want := range []string{
+		`rocketmq_namesrv_connections{transport="grpc"} 1`,
+		`rocketmq_namesrv_connections{transport="native"} 1`,
+		"rocketmq_namesrv_clusters 0",
+	} {
+		if !strings.Contains(string(body), want+"\n") {
+			t.Errorf("no %s in\n%s", want, body)
+		}
+	}
+}
+
+func TestServerAuditLog(t *testing.T) {
+	auditLog := logging.AuditLog
+	t.Cleanup(func() { logging.AuditLog = auditLog })
+
+	conf := testConfig(t)
+	dir := filepath.Dir(conf.KVConfigPath)
+	conf.ACLEnable = true
+	conf.ACLConfigPath = filepath.Join(dir, "plain_acl.toml")
+	conf.ACLAuditLogPath = filepath.Join(dir, "acl_audit.log")
+	acl := "[[accounts]]\naccessKey = \"client\"\nsecretKey = \"client-secret\"\nrole = \"readonly\"\n"
+	if err := ioutil.WriteFile(conf.ACLConfigPath, []byte(acl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown(context.Background())
+
+	c := remote.NewClient(s.Addr().String())
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+	request := &pb.RemoteCommand{Code: int32(pb.RequestCode_GET_BROKER_CLUSTER_INFO)}
+	response, err := c.InvokeSync(context.Background(), request, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Code != int32(pb.ResponseCode_ACCESS_DENIED) {
+		t.Fatalf("response code = %v", pb.ResponseCode(response.Code))
+	}
+
+	data, err := ioutil.ReadFile(conf.ACLAuditLogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Request denied") {
+		t.Fatalf("audit log = %q", data)
+	}
+}
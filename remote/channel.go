@@ -0,0 +1,72 @@
+package remote
+
+import (
+	"context"
+	"go.uber.org/atomic"
+	. "rocketmq-go/common"
+	pb "rocketmq-go/common/proto"
+	"sync"
+)
+
+type channelKey struct{}
+
+// Channel is the connection a request came in on, processors can keep it to
+// push commands back to the peer later.
+type Channel interface {
+	RemoteAddr() string
+	Send(*pb.RemoteCommand) error
+	Done() <-chan struct{}
+}
+
+type streamChannel struct {
+	mu         sync.Mutex
+	addr       string
+	stream     pb.RemoteRPC_ProcessServer
+	lastActive atomic.Int64
+}
+
+func newStreamChannel(addr string, stream pb.RemoteRPC_ProcessServer) *streamChannel {
+	c := &streamChannel{
+		addr:   addr,
+		stream: stream,
+	}
+	c.touch()
+	return c
+}
+
+func (c *streamChannel) touch() {
+	c.lastActive.Store(CurrentTimeMills())
+}
+
+func (c *streamChannel) idleMills() int64 {
+	return CurrentTimeMills() - c.lastActive.Load()
+}
+
+func (c *streamChannel) RemoteAddr() string {
+	return c.addr
+}
+
+// Send is safe to call from several goroutines, grpc streams are not.
+func (c *streamChannel) Send(cmd *pb.RemoteCommand) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.touch()
+	return c.stream.Send(cmd)
+}
+
+func (c *streamChannel) Done() <-chan struct{} {
+	return c.stream.Context().Done()
+}
+
+// WithChannel returns a context carrying ch, for listeners other than
+// the gRPC one to pass their connections to the processors.
+func WithChannel(ctx context.Context, ch Channel) context.Context {
+	return context.WithValue(ctx, channelKey{}, ch)
+}
+
+// ChannelFromContext returns the channel the request being processed came in
+// on, or nil if there is none.
+func ChannelFromContext(ctx context.Context) Channel {
+	ch, _ := ctx.Value(channelKey{}).(Channel)
+	return ch
+}
@@ -0,0 +1,10 @@
+package remote
+
+// ChannelEventListener is told about the lifecycle of the connections of a
+// Server, remoteAddr is the address of the peer.
+type ChannelEventListener interface {
+	OnChannelConnect(remoteAddr string)
+	OnChannelClose(remoteAddr string)
+	OnChannelException(remoteAddr string, err error)
+	OnChannelIdle(remoteAddr string)
+}
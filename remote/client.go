@@ -2,88 +2,661 @@ package remote
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"io"
-	"log"
+	"math/rand"
+	"net"
+	. "rocketmq-go/common"
+	"rocketmq-go/common/clock"
 	pb "rocketmq-go/common/proto"
+	. "rocketmq-go/logging"
+	"strings"
+	"sync"
+	"time"
 )
 
+const (
+	defaultRequestTimeout = 3 * time.Second
+	defaultConnectTimeout = 3 * time.Second
+	defaultMinBackoff     = 100 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+	defaultKeepalive      = 30 * time.Second
+	defaultStableTime     = 10 * time.Second
+	defaultJitter         = 0.2
+)
+
+var (
+	ErrTimeout      = errors.New("remote: wait response timeout")
+	ErrConnClosed   = errors.New("remote: connection closed before response")
+	ErrClientClosed = errors.New("remote: client closed")
+	ErrNoAddr       = errors.New("remote: no name server address")
+
+	errServerShutdown = errors.New("remote: name server shutdown")
+)
+
+type ConnState int32
+
+const (
+	Idle ConnState = iota
+	Connecting
+	Ready
+	TransientFailure
+	Shutdown
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case Idle:
+		return "IDLE"
+	case Connecting:
+		return "CONNECTING"
+	case Ready:
+		return "READY"
+	case TransientFailure:
+		return "TRANSIENT_FAILURE"
+	case Shutdown:
+		return "SHUTDOWN"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+type InvokeCallback func(*ResponseFuture)
+
+// ResponseFuture is a request waiting for its response.
+type ResponseFuture struct {
+	opaque   int32
+	deadline time.Time
+	callback InvokeCallback
+
+	once            sync.Once
+	done            chan struct{}
+	responseCommand *pb.RemoteCommand
+	err             error
+}
+
+func newResponseFuture(opaque int32, timeout time.Duration, callback InvokeCallback) *ResponseFuture {
+	return &ResponseFuture{
+		opaque:   opaque,
+		deadline: time.Now().Add(timeout),
+		callback: callback,
+		done:     make(chan struct{}),
+	}
+}
+
+func (f *ResponseFuture) ResponseCommand() *pb.RemoteCommand {
+	return f.responseCommand
+}
+
+func (f *ResponseFuture) Err() error {
+	return f.err
+}
+
+func (f *ResponseFuture) complete(response *pb.RemoteCommand, err error) {
+	f.once.Do(func() {
+		f.responseCommand = response
+		f.err = err
+		close(f.done)
+		if f.callback != nil {
+			go f.callback(f)
+		}
+	})
+}
+
+// sendRequest is a command queued for sending, future is nil for oneway
+// requests and responses.
+type sendRequest struct {
+	cmd    *pb.RemoteCommand
+	future *ResponseFuture
+}
+
+// Client keeps a stream to one of the name servers of a semicolon separated
+// address list, it reconnects with exponential backoff and fails over to the
+// next address whenever the stream breaks. The topic route subscriptions
+// are sent again on every new stream.
 type Client struct {
-	addr string
+	// Processor handles the requests pushed by the server, it may be nil.
+	Processor
+	// RequestTimeout is the timeout of InvokeAsync and InvokeOneway.
+	RequestTimeout time.Duration
+	ConnectTimeout time.Duration
+	MinBackoff     time.Duration
+	MaxBackoff     time.Duration
+	// StableTime is how long a stream has to stay up for the backoff to
+	// start over from MinBackoff once it breaks.
+	StableTime time.Duration
+	// Jitter is the largest fraction of the backoff added at random, so
+	// the clients of a server gone do not all redial at once.
+	Jitter float64
+	// TLS dials the name servers over TLS when set, it must be set before
+	// Start.
+	TLS *TLSConfig
+	// Signer is called on every request right before it is queued, e.g. to
+	// sign it for the ACL of the name server.
+	Signer func(*pb.RemoteCommand)
+	// Dialer connects to the name servers instead of TCP when set, e.g. to
+	// an in-memory listener. It must be set before Start.
+	Dialer func(ctx context.Context, addr string) (net.Conn, error)
+	// Clock times the backoff and the keepalives, it must be set before
+	// Start.
+	Clock clock.Clock
+	// Keepalive is how often the subscriptions are sent again while
+	// connected, which keeps the server from closing the stream as idle.
+	Keepalive time.Duration
+
+	addrs    []string
+	addrIdx  atomic.Int32
+	state    atomic.Int32
+	sendChan chan *sendRequest
+	stopOnce sync.Once
+	stopChan chan struct{}
+
+	certReloader *certReloader
+
+	responseLock  sync.Mutex
+	responseTable map[int32]*ResponseFuture
 
-	cli pb.RemoteRPCClient
+	subscribeLock sync.Mutex
+	subscribed    map[string]bool
 
-	sendChan chan *pb.RemoteCommand
-	recvChan chan *pb.RemoteCommand
+	// The requests pushed by the server, the Processor takes them one at a
+	// time in arrival order
+	pushLock   sync.Mutex
+	pushes     []*pb.RemoteCommand
+	pushWakeup chan struct{}
 }
 
 func NewClient(addr string) *Client {
+	addrs := make([]string, 0)
+	for _, a := range strings.Split(addr, ";") {
+		if a = strings.TrimSpace(a); a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+
 	return &Client{
-		addr: addr,
-		sendChan: make(chan *pb.RemoteCommand, 10),
-		recvChan: make(chan *pb.RemoteCommand, 10),
+		RequestTimeout: defaultRequestTimeout,
+		ConnectTimeout: defaultConnectTimeout,
+		MinBackoff:     defaultMinBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		StableTime:     defaultStableTime,
+		Jitter:         defaultJitter,
+		Keepalive:      defaultKeepalive,
+		Clock:          clock.Real,
+		addrs:          addrs,
+		sendChan:       make(chan *sendRequest, 1024),
+		stopChan:       make(chan struct{}),
+		responseTable:  make(map[int32]*ResponseFuture),
+		subscribed:     make(map[string]bool),
+		pushWakeup:     make(chan struct{}, 1),
+	}
+}
+
+// Start connects in the background, requests made before the connection is
+// ready are queued. A client failing to start is stopped.
+func (c *Client) Start() error {
+	if len(c.addrs) == 0 {
+		c.Stop()
+		return ErrNoAddr
+	}
+	if c.TLS != nil {
+		reloader, err := newCertReloader(c.TLS)
+		if err != nil {
+			c.Stop()
+			return fmt.Errorf("remote: load tls certificates: %w", err)
+		}
+		c.certReloader = reloader
 	}
+
+	go c.scanResponseTable()
+	go c.processPushes()
+	go c.run()
+	return nil
 }
 
-func (c *Client) Start() {
-	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithInsecure())
-	opts = append(opts, grpc.WithBlock())
-	conn, err := grpc.Dial(c.addr, opts...)
+func (c *Client) Stop() {
+	c.stopOnce.Do(func() {
+		c.state.Store(int32(Shutdown))
+		close(c.stopChan)
+		c.failPending(ErrClientClosed)
+		for {
+			select {
+			case req := <-c.sendChan:
+				if req.future != nil {
+					req.future.complete(nil, ErrClientClosed)
+				}
+			default:
+				return
+			}
+		}
+	})
+}
+
+func (c *Client) State() ConnState {
+	return ConnState(c.state.Load())
+}
+
+// Addr returns the name server the client is connected or connecting to.
+func (c *Client) Addr() string {
+	if len(c.addrs) == 0 {
+		return ""
+	}
+	return c.addrs[int(c.addrIdx.Load())%len(c.addrs)]
+}
+
+func (c *Client) setState(state ConnState) {
+	if c.State() == Shutdown {
+		return
+	}
+	c.state.Store(int32(state))
+}
+
+func (c *Client) stopped() bool {
+	select {
+	case <-c.stopChan:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) run() {
+	backoff := c.MinBackoff
+	for !c.stopped() {
+		addr := c.Addr()
+		c.setState(Connecting)
+		up, err := c.connect(addr)
+		c.failPending(ErrConnClosed)
+		if c.stopped() {
+			return
+		}
+
+		c.setState(TransientFailure)
+		Log.Warn("Connection to name server broken",
+			zap.String("addr", addr),
+			zap.Error(err))
+		c.addrIdx.Inc()
+
+		// A stream dropped soon after it was set up counts as a failed dial,
+		// or a server taking and dropping streams keeps the clients dialing
+		if up >= c.StableTime {
+			backoff = c.MinBackoff
+		}
+
+		wait := backoff + time.Duration(rand.Float64()*c.Jitter*float64(backoff))
+		select {
+		case <-c.Clock.After(wait):
+		case <-c.stopChan:
+			return
+		}
+		backoff *= 2
+		if backoff > c.MaxBackoff {
+			backoff = c.MaxBackoff
+		}
+	}
+}
+
+// connect serves one stream to addr until it breaks, up is how long the
+// stream was established, zero when it never was.
+func (c *Client) connect(addr string) (up time.Duration, err error) {
+	transport := grpc.WithInsecure()
+	if c.certReloader != nil {
+		transport = grpc.WithTransportCredentials(credentials.NewTLS(c.certReloader.clientConfig()))
+	}
+
+	opts := []grpc.DialOption{transport, grpc.WithBlock()}
+	if c.Dialer != nil {
+		opts = append(opts, grpc.WithContextDialer(c.Dialer))
+	}
+
+	dialCtx, cancel := context.WithTimeout(context.Background(), c.ConnectTimeout)
+	conn, err := grpc.DialContext(dialCtx, addr, opts...)
+	cancel()
 	if err != nil {
-		log.Fatalf("fail to dial: %v", err)
+		return 0, err
 	}
 	defer conn.Close()
-	c.cli = pb.NewRemoteRPCClient(conn)
-	c.process()
-}
 
-func (c *Client) process() {
-	stream, err := c.cli.Process(context.Background())
+	streamCtx, streamCancel := context.WithCancel(context.Background())
+	defer streamCancel()
+	stream, err := pb.NewRemoteRPCClient(conn).Process(streamCtx)
 	if err != nil {
-		log.Fatalf("%v.RouteChat(_) = _, %v", c.cli, err)
+		return 0, err
+	}
+	c.setState(Ready)
+	Log.Info("Connected to name server", zap.String("addr", addr))
+	// The returns from here on report how long the stream was up
+	readyAt := c.Clock.Now()
+	defer func() {
+		up = c.Clock.Now().Sub(readyAt)
+	}()
+
+	var keepalive <-chan time.Time
+	if c.Keepalive > 0 {
+		ticker := c.Clock.NewTicker(c.Keepalive)
+		defer ticker.Stop()
+		keepalive = ticker.C()
 	}
-	exitChan := make(chan struct{})
+	// The new server knows nothing of the subscriptions
+	if err := c.resubscribe(stream); err != nil {
+		return 0, err
+	}
+
+	errChan := make(chan error, 1)
+	shutdownChan := make(chan struct{}, 1)
 	go func() {
 		for {
-			resp, err := stream.Recv()
+			cmd, err := stream.Recv()
 			if err != nil {
-				if err == io.EOF {
-					close(exitChan)
-					return
-				}
-				log.Printf("Failed to receive a response: %v", err)
+				errChan <- err
 				return
 			}
-			c.recvChan <- resp
+			if cmd.Code == int32(pb.RequestCode_NOTIFY_SHUTDOWN) && !IsResponseType(cmd) {
+				select {
+				case shutdownChan <- struct{}{}:
+				default:
+				}
+				continue
+			}
+			c.processCommand(cmd)
 		}
 	}()
 
-	go func() {
-		for {
-			req := <- c.sendChan
-			if err := stream.Send(req); err != nil {
-				if err == io.EOF {
-					close(exitChan)
-					return
-				} else {
-					log.Printf("Fail to request: %v", err)
-					return
+	// Nil once the server is shutting down, new requests wait for the
+	// next server while the pending ones are answered
+	sendChan := c.sendChan
+	for {
+		select {
+		case req := <-sendChan:
+			if req.future != nil {
+				if time.Now().After(req.future.deadline) {
+					req.future.complete(nil, ErrTimeout)
+					continue
+				}
+				c.responseLock.Lock()
+				c.responseTable[req.cmd.Opaque] = req.future
+				c.responseLock.Unlock()
+			}
+			if err := stream.Send(req.cmd); err != nil {
+				return 0, err
+			}
+		case <-keepalive:
+			if sendChan == nil {
+				continue
+			}
+			if err := c.resubscribe(stream); err != nil {
+				return 0, err
+			}
+		case <-shutdownChan:
+			Log.Info("Name server is shutting down, wait for the pending responses",
+				zap.String("addr", addr))
+			sendChan = nil
+			_ = stream.CloseSend()
+		case err := <-errChan:
+			if err == io.EOF {
+				err = ErrConnClosed
+				if sendChan == nil {
+					err = errServerShutdown
 				}
+			}
+			return 0, err
+		case <-c.stopChan:
+			_ = stream.CloseSend()
+			return 0, ErrClientClosed
+		}
+	}
+}
+
+// resubscribe sends the topics subscribed on stream, if any.
+func (c *Client) resubscribe(stream pb.RemoteRPC_ProcessClient) error {
+	c.subscribeLock.Lock()
+	topics := make([]string, 0, len(c.subscribed))
+	for topic := range c.subscribed {
+		topics = append(topics, topic)
+	}
+	c.subscribeLock.Unlock()
+	if len(topics) == 0 {
+		return nil
+	}
+
+	request := &pb.RemoteCommand{
+		Code:   int32(pb.RequestCode_SUBSCRIBE_TOPIC_ROUTE),
+		Header: Serializable(&pb.SubscribeTopicRouteRequestHeader{Topics: topics}),
+		Opaque: NextOpaque(),
+	}
+	if c.Signer != nil {
+		c.Signer(request)
+	}
+	future := newResponseFuture(request.Opaque, c.RequestTimeout, func(f *ResponseFuture) {
+		if f.err == nil && f.responseCommand.Code == int32(pb.ResponseCode_SUCCESS) {
+			return
+		}
+		Log.Warn("Subscribe topic route again failed",
+			zap.Strings("topics", topics),
+			zap.Error(f.err))
+	})
+	c.responseLock.Lock()
+	c.responseTable[request.Opaque] = future
+	c.responseLock.Unlock()
+	return stream.Send(request)
+}
 
+// failPending fails the requests sent but not answered yet, their
+// responses will never come once the stream is gone.
+func (c *Client) failPending(err error) {
+	c.responseLock.Lock()
+	pending := c.responseTable
+	c.responseTable = make(map[int32]*ResponseFuture)
+	c.responseLock.Unlock()
+
+	for _, future := range pending {
+		future.complete(nil, err)
+	}
+}
+
+func (c *Client) processCommand(cmd *pb.RemoteCommand) {
+	if IsResponseType(cmd) {
+		c.processResponseCommand(cmd)
+		return
+	}
+
+	if c.Processor == nil {
+		Log.Warn("No processor for request", zap.Int32("code", cmd.Code))
+		return
+	}
+
+	// Queued rather than run here, the Processor may invoke requests whose
+	// responses this receive loop reads
+	c.pushLock.Lock()
+	c.pushes = append(c.pushes, cmd)
+	c.pushLock.Unlock()
+	select {
+	case c.pushWakeup <- struct{}{}:
+	default:
+	}
+}
+
+// processPushes runs the Processor on the pushed requests one at a time, so
+// e.g. an older route change is never handled after a newer one.
+func (c *Client) processPushes() {
+	for {
+		select {
+		case <-c.pushWakeup:
+		case <-c.stopChan:
+			return
+		}
+
+		for !c.stopped() {
+			c.pushLock.Lock()
+			if len(c.pushes) == 0 {
+				c.pushLock.Unlock()
+				break
+			}
+			cmd := c.pushes[0]
+			c.pushes[0] = nil
+			c.pushes = c.pushes[1:]
+			c.pushLock.Unlock()
+
+			resp := c.Processor(context.Background(), cmd)
+			if resp == nil || IsOnewayRPC(cmd) {
+				continue
 			}
+			resp.Opaque = cmd.Opaque
+			MarkResponseType(resp)
+			_ = c.send(&sendRequest{cmd: resp}, c.RequestTimeout)
 		}
-	}()
+	}
+}
+
+func (c *Client) processResponseCommand(cmd *pb.RemoteCommand) {
+	c.responseLock.Lock()
+	future, ok := c.responseTable[cmd.Opaque]
+	delete(c.responseTable, cmd.Opaque)
+	c.responseLock.Unlock()
 
-	<-exitChan
-	_ = stream.CloseSend()
+	if !ok {
+		Log.Warn("Receive response, but no request matched", zap.Int32("opaque", cmd.Opaque))
+		return
+	}
+	future.complete(cmd, nil)
+}
+
+// scanResponseTable fails the requests whose response did not come in time.
+func (c *Client) scanResponseTable() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			expired := make([]*ResponseFuture, 0)
+			c.responseLock.Lock()
+			for opaque, future := range c.responseTable {
+				if now.After(future.deadline) {
+					delete(c.responseTable, opaque)
+					expired = append(expired, future)
+				}
+			}
+			c.responseLock.Unlock()
+
+			for _, future := range expired {
+				future.complete(nil, ErrTimeout)
+			}
+		case <-c.stopChan:
+			return
+		}
+	}
 }
 
-func (c *Client) Send(request *pb.RemoteCommand) {
-	c.sendChan <- request
+func (c *Client) send(req *sendRequest, timeout time.Duration) error {
+	if c.stopped() {
+		return ErrClientClosed
+	}
+	if c.Signer != nil && !IsResponseType(req.cmd) {
+		c.Signer(req.cmd)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case c.sendChan <- req:
+		return nil
+	case <-timer.C:
+		return ErrTimeout
+	case <-c.stopChan:
+		return ErrClientClosed
+	}
 }
 
-func (c *Client) Recv() *pb.RemoteCommand {
-	response := <- c.recvChan
-	return response
-}
\ No newline at end of file
+func (c *Client) removeFuture(opaque int32) {
+	c.responseLock.Lock()
+	delete(c.responseTable, opaque)
+	c.responseLock.Unlock()
+}
+
+// InvokeSync sends request and waits for its response until timeout or ctx
+// is done.
+func (c *Client) InvokeSync(
+	ctx context.Context, request *pb.RemoteCommand, timeout time.Duration) (*pb.RemoteCommand, error) {
+	request.Opaque = NextOpaque()
+	future := newResponseFuture(request.Opaque, timeout, nil)
+	if err := c.send(&sendRequest{request, future}, timeout); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-future.done:
+		return future.responseCommand, future.err
+	case <-timer.C:
+		c.removeFuture(future.opaque)
+		future.complete(nil, ErrTimeout)
+		return nil, ErrTimeout
+	case <-ctx.Done():
+		c.removeFuture(future.opaque)
+		future.complete(nil, ctx.Err())
+		return nil, ctx.Err()
+	}
+}
+
+// InvokeAsync sends request, callback is called with the response or the
+// error once it is known.
+func (c *Client) InvokeAsync(request *pb.RemoteCommand, callback InvokeCallback) error {
+	request.Opaque = NextOpaque()
+	future := newResponseFuture(request.Opaque, c.RequestTimeout, callback)
+	return c.send(&sendRequest{request, future}, c.RequestTimeout)
+}
+
+// InvokeOneway sends request without waiting for any response.
+func (c *Client) InvokeOneway(request *pb.RemoteCommand) error {
+	request.Opaque = NextOpaque()
+	MarkOnewayRPC(request)
+	return c.send(&sendRequest{cmd: request}, c.RequestTimeout)
+}
+
+// SubscribeTopicRoute asks the server to push NOTIFY_TOPIC_ROUTE_CHANGED of
+// topics to the Processor. The subscription is kept over reconnects and
+// failovers until UnSubscribeTopicRoute.
+func (c *Client) SubscribeTopicRoute(ctx context.Context, topics []string, timeout time.Duration) error {
+	c.subscribeLock.Lock()
+	for _, topic := range topics {
+		c.subscribed[topic] = true
+	}
+	c.subscribeLock.Unlock()
+
+	header := &pb.SubscribeTopicRouteRequestHeader{Topics: topics}
+	return c.invokeSubscription(ctx, pb.RequestCode_SUBSCRIBE_TOPIC_ROUTE, Serializable(header), timeout)
+}
+
+// UnSubscribeTopicRoute stops the pushes of topics.
+func (c *Client) UnSubscribeTopicRoute(ctx context.Context, topics []string, timeout time.Duration) error {
+	c.subscribeLock.Lock()
+	for _, topic := range topics {
+		delete(c.subscribed, topic)
+	}
+	c.subscribeLock.Unlock()
+
+	header := &pb.UnSubscribeTopicRouteRequestHeader{Topics: topics}
+	return c.invokeSubscription(ctx, pb.RequestCode_UNSUBSCRIBE_TOPIC_ROUTE, Serializable(header), timeout)
+}
+
+func (c *Client) invokeSubscription(
+	ctx context.Context, code pb.RequestCode, header []byte, timeout time.Duration) error {
+	response, err := c.InvokeSync(ctx, &pb.RemoteCommand{Code: int32(code), Header: header}, timeout)
+	if err != nil {
+		return err
+	}
+	if response.Code != int32(pb.ResponseCode_SUCCESS) {
+		return fmt.Errorf("remote: %v failed, code %d: %s", code, response.Code, response.Remark)
+	}
+	return nil
+}
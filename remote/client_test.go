@@ -1,15 +1,49 @@
 package remote
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"net"
 	"rocketmq-go/common"
+	"rocketmq-go/common/clock"
 	pb "rocketmq-go/common/proto"
+	"sync"
 	"testing"
+	"time"
 )
 
+func startServer(t *testing.T, processor Processor) (*Server, *Client) {
+	s := NewServer("127.0.0.1:0")
+	s.RegisterDefaultProcessor(processor, nil)
+	s.Start()
+
+	c := NewClient(s.Addr().String())
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Stop)
+	t.Cleanup(s.Stop)
+	return s, c
+}
+
+func echoProcessor(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+	return &pb.RemoteCommand{
+		Code:   int32(pb.ResponseCode_SUCCESS),
+		Remark: request.Remark,
+	}
+}
+
 func TestRegisterBrokerHeader(t *testing.T) {
-	c := NewClient("0.0.0.0:9876")
-	go c.Start()
+	received := make(chan *pb.RegisterBrokerRequestHeader, 1)
+	_, c := startServer(t, func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		header := &pb.RegisterBrokerRequestHeader{}
+		if err := common.Deserializable(request.Header, header, false); err != nil {
+			return &pb.RemoteCommand{Code: int32(pb.ResponseCode_SYSTEM_ERROR), Remark: err.Error()}
+		}
+		received <- header
+		return &pb.RemoteCommand{Code: int32(pb.ResponseCode_SUCCESS)}
+	})
 
 	header := &pb.RegisterBrokerRequestHeader{
 		BrokerName: "broker-a",
@@ -23,27 +57,421 @@ func TestRegisterBrokerHeader(t *testing.T) {
 		Code: int32(pb.RequestCode_REGISTER_BROKER),
 		Header: common.Serializable(header),
 	}
-	c.Send(request)
-	response := c.Recv()
-	log.Printf("registerBroker, code: %d, remark: %s", response.Code, response.Remark)
-}
-
-//func TestUnRegisterBrokerHeader(t *testing.T) {
-//	c := NewClient("0.0.0.0:9876")
-//	go c.Start()
-//
-//	header := &pb.UnRegisterBrokerHeader{
-//		BrokerName: "broker-a",
-//		BrokerAddr: "192.168.1.1:8675",
-//		ClusterName: "cluster",
-//		BrokerId: 0,
-//	}
-//	request := &pb.RemoteCommand{
-//		Code: int32(pb.RequestCode_UNREGISTER_BROKER),
-//		Header: remote.SerializableM(header),
-//	}
-//	c.Send(request)
-//	response := c.Recv()
-//	log.Printf("unregisterBroker, code: %d, remark: %s", response.Code, response.Remark)
-//}
+	response, err := c.InvokeSync(context.Background(), request, 3*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Code != int32(pb.ResponseCode_SUCCESS) {
+		t.Fatalf("registerBroker, code: %d, remark: %s", response.Code, response.Remark)
+	}
+
+	got := <-received
+	if got.BrokerName != header.BrokerName || got.BrokerAddr != header.BrokerAddr {
+		t.Fatalf("server decoded %v, want %v", got, header)
+	}
+}
+
+func TestInvokeSyncConcurrent(t *testing.T) {
+	_, c := startServer(t, echoProcessor)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			remark := fmt.Sprintf("request-%d", i)
+			request := &pb.RemoteCommand{Remark: remark}
+			response, err := c.InvokeSync(context.Background(), request, 3*time.Second)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if response.Remark != remark || response.Opaque != request.Opaque {
+				errs <- fmt.Errorf("request %s got response %s", remark, response.Remark)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestInvokeAsync(t *testing.T) {
+	_, c := startServer(t, echoProcessor)
+
+	done := make(chan *ResponseFuture, 1)
+	err := c.InvokeAsync(&pb.RemoteCommand{Remark: "async"}, func(f *ResponseFuture) {
+		done <- f
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case f := <-done:
+		if f.Err() != nil || f.ResponseCommand().Remark != "async" {
+			t.Fatalf("response %v, err %v", f.ResponseCommand(), f.Err())
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("callback not called")
+	}
+}
+
+func TestInvokeOneway(t *testing.T) {
+	received := make(chan *pb.RemoteCommand, 1)
+	_, c := startServer(t, func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		received <- request
+		return echoProcessor(ctx, request)
+	})
+
+	if err := c.InvokeOneway(&pb.RemoteCommand{Remark: "oneway"}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case request := <-received:
+		if !IsOnewayRPC(request) {
+			t.Fatal("oneway flag not set")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("oneway request not received")
+	}
+}
+
+func TestInvokeSyncTimeout(t *testing.T) {
+	_, c := startServer(t, func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		time.Sleep(500 * time.Millisecond)
+		return echoProcessor(ctx, request)
+	})
+
+	_, err := c.InvokeSync(context.Background(), &pb.RemoteCommand{}, 100*time.Millisecond)
+	if err != ErrTimeout {
+		t.Fatalf("err = %v, want ErrTimeout", err)
+	}
+}
+
+func TestClientFailover(t *testing.T) {
+	s := NewServer("127.0.0.1:0")
+	s.RegisterDefaultProcessor(echoProcessor, nil)
+	s.Start()
+	defer s.Stop()
+
+	// Nothing listens on the first address
+	c := NewClient("127.0.0.1:1;" + s.Addr().String())
+	c.ConnectTimeout = 200 * time.Millisecond
+	c.MinBackoff = 10 * time.Millisecond
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	response, err := c.InvokeSync(context.Background(), &pb.RemoteCommand{Remark: "failover"}, 3*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Remark != "failover" {
+		t.Fatalf("response remark = %s", response.Remark)
+	}
+	if c.State() != Ready || c.Addr() != s.Addr().String() {
+		t.Fatalf("state = %s, addr = %s", c.State(), c.Addr())
+	}
+}
+
+func TestInFlightRequestFailsWhenServerGone(t *testing.T) {
+	received := make(chan struct{})
+	s, c := startServer(t, func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		close(received)
+		<-ctx.Done()
+		return nil
+	})
+
+	go func() {
+		<-received
+		s.Stop()
+	}()
+
+	_, err := c.InvokeSync(context.Background(), &pb.RemoteCommand{}, 5*time.Second)
+	if err != ErrConnClosed {
+		t.Fatalf("err = %v, want ErrConnClosed", err)
+	}
+}
+
+func TestClientStop(t *testing.T) {
+	c := NewClient("127.0.0.1:1")
+	c.ConnectTimeout = 100 * time.Millisecond
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	c.Stop()
+
+	if c.State() != Shutdown {
+		t.Fatalf("state = %s, want SHUTDOWN", c.State())
+	}
+	if _, err := c.InvokeSync(context.Background(), &pb.RemoteCommand{}, time.Second); err != ErrClientClosed {
+		t.Fatalf("err = %v, want ErrClientClosed", err)
+	}
+}
+
+// subscribeServer records the topics of the SUBSCRIBE_TOPIC_ROUTE requests,
+// listener may be nil.
+func subscribeServer(t *testing.T, listener ChannelEventListener, maxIdleTime time.Duration) (*Server, chan []string) {
+	subscribed := make(chan []string, 100)
+	s := NewServer("127.0.0.1:0")
+	s.RegisterDefaultProcessor(func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		if request.Code == int32(pb.RequestCode_SUBSCRIBE_TOPIC_ROUTE) {
+			header := &pb.SubscribeTopicRouteRequestHeader{}
+			if err := common.Deserializable(request.Header, header, false); err != nil {
+				return &pb.RemoteCommand{Code: int32(pb.ResponseCode_SYSTEM_ERROR), Remark: err.Error()}
+			}
+			subscribed <- header.Topics
+		}
+		return &pb.RemoteCommand{Code: int32(pb.ResponseCode_SUCCESS)}
+	}, nil)
+	s.ChannelEventListener = listener
+	s.ChannelMaxIdleTime = maxIdleTime
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Stop)
+	return s, subscribed
+}
 
+func waitSubscribed(t *testing.T, subscribed chan []string, topic string) {
+	t.Helper()
+	select {
+	case topics := <-subscribed:
+		if len(topics) != 1 || topics[0] != topic {
+			t.Fatalf("subscribed %v, want %s", topics, topic)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("%s not subscribed", topic)
+	}
+}
+
+func TestSubscriptionsSentAfterFailover(t *testing.T) {
+	a, subscribedA := subscribeServer(t, nil, time.Minute)
+	b, subscribedB := subscribeServer(t, nil, time.Minute)
+
+	c := NewClient(a.Addr().String() + ";" + b.Addr().String())
+	c.MinBackoff = 10 * time.Millisecond
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	if err := c.SubscribeTopicRoute(context.Background(), []string{"TopicA"}, 3*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	waitSubscribed(t, subscribedA, "TopicA")
+
+	a.Stop()
+	waitSubscribed(t, subscribedB, "TopicA")
+
+	if err := c.UnSubscribeTopicRoute(context.Background(), []string{"TopicA"}, 3*time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type idleListener struct {
+	idle chan string
+}
+
+func (l *idleListener) OnChannelConnect(remoteAddr string)              {}
+func (l *idleListener) OnChannelClose(remoteAddr string)                {}
+func (l *idleListener) OnChannelException(remoteAddr string, err error) {}
+func (l *idleListener) OnChannelIdle(remoteAddr string) {
+	l.idle <- remoteAddr
+}
+
+func TestKeepaliveKeepsSubscribedChannel(t *testing.T) {
+	listener := &idleListener{idle: make(chan string, 10)}
+	s, subscribed := subscribeServer(t, listener, 200*time.Millisecond)
+
+	c := NewClient(s.Addr().String())
+	c.Keepalive = 50 * time.Millisecond
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+	if err := c.SubscribeTopicRoute(context.Background(), []string{"TopicA"}, 3*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// The subscription and then the keepalives, past the idle time
+	for i := 0; i < 8; i++ {
+		waitSubscribed(t, subscribed, "TopicA")
+	}
+	select {
+	case addr := <-listener.idle:
+		t.Fatalf("subscribed channel %s closed as idle", addr)
+	default:
+	}
+}
+
+func TestClientStartFails(t *testing.T) {
+	if err := NewClient(" ; ").Start(); err != ErrNoAddr {
+		t.Fatalf("err = %v, want ErrNoAddr", err)
+	}
+
+	c := NewClient("127.0.0.1:1")
+	c.TLS = &TLSConfig{CertFile: "/no/cert.pem", KeyFile: "/no/key.pem"}
+	if err := c.Start(); err == nil {
+		t.Fatal("start with missing certificates succeeded")
+	}
+	if c.State() != Shutdown {
+		t.Fatalf("state = %s, want SHUTDOWN", c.State())
+	}
+}
+
+func TestClientBackoff(t *testing.T) {
+	start := time.Now()
+	fake := clock.NewFake(start)
+
+	// The first dial of every attempt by the time of the fake clock, the
+	// dialer may be called again within an attempt
+	var mu sync.Mutex
+	var attempts []string
+	c := NewClient("a;b")
+	c.Clock = fake
+	c.ConnectTimeout = 20 * time.Millisecond
+	c.MinBackoff = time.Second
+	c.MaxBackoff = 3 * time.Second
+	c.Jitter = 0
+	c.Dialer = func(ctx context.Context, addr string) (net.Conn, error) {
+		attempt := fmt.Sprintf("%s after %v", addr, fake.Now().Sub(start))
+		mu.Lock()
+		if len(attempts) == 0 || attempts[len(attempts)-1] != attempt {
+			attempts = append(attempts, attempt)
+		}
+		mu.Unlock()
+		return nil, errors.New("refused")
+	}
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	for _, backoff := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		fake.BlockUntil(1)
+		fake.Advance(backoff - time.Millisecond)
+		fake.Advance(time.Millisecond)
+	}
+	fake.BlockUntil(1)
+
+	// Every failure moves on to the next address after the backoff, which
+	// doubles up to MaxBackoff
+	want := []string{"a after 0s", "b after 1s", "a after 3s", "b after 6s", "a after 9s"}
+	mu.Lock()
+	defer mu.Unlock()
+	if fmt.Sprint(attempts) != fmt.Sprint(want) {
+		t.Fatalf("attempts = %v, want %v", attempts, want)
+	}
+}
+
+func TestPushesProcessedInOrder(t *testing.T) {
+	s := NewServer("127.0.0.1:0")
+	s.RegisterDefaultProcessor(func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		ch := ChannelFromContext(ctx)
+		for i := 0; i < 10; i++ {
+			push := &pb.RemoteCommand{Code: int32(pb.RequestCode_NOTIFY_TOPIC_ROUTE_CHANGED), Remark: fmt.Sprint(i)}
+			MarkOnewayRPC(push)
+			if err := ch.Send(push); err != nil {
+				return &pb.RemoteCommand{Code: int32(pb.ResponseCode_SYSTEM_ERROR), Remark: err.Error()}
+			}
+		}
+		return &pb.RemoteCommand{Code: int32(pb.ResponseCode_SUCCESS)}
+	}, nil)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	// The earlier pushes take longer, run at once they would finish last
+	processed := make(chan string, 10)
+	c := NewClient(s.Addr().String())
+	c.Processor = func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		var i int
+		fmt.Sscan(request.Remark, &i)
+		time.Sleep(time.Duration(10-i) * 5 * time.Millisecond)
+		processed <- request.Remark
+		return nil
+	}
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+	if _, err := c.InvokeSync(context.Background(), &pb.RemoteCommand{}, 3*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		select {
+		case remark := <-processed:
+			if remark != fmt.Sprint(i) {
+				t.Fatalf("push %s processed as number %d", remark, i)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("push %d not processed", i)
+		}
+	}
+}
+
+func TestClientBacksOffDroppedStreams(t *testing.T) {
+	// The server drops every stream right after taking it
+	s := NewServer("127.0.0.1:0")
+	s.RegisterDefaultProcessor(echoProcessor, nil)
+	s.ChannelMaxIdleTime = 10 * time.Millisecond
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	start := time.Now()
+	fake := clock.NewFake(start)
+	dials := make(chan time.Duration, 10)
+	c := NewClient(s.Addr().String())
+	c.Clock = fake
+	c.Keepalive = 0
+	c.MinBackoff = time.Second
+	c.Jitter = 0
+	c.Dialer = func(ctx context.Context, addr string) (net.Conn, error) {
+		dials <- fake.Now().Sub(start)
+		return net.Dial("tcp", addr)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	// The streams never stay up for StableTime, so the backoff keeps
+	// doubling instead of redialing right away
+	steps := []struct {
+		at      time.Duration
+		backoff time.Duration
+	}{{0, time.Second}, {time.Second, 2 * time.Second}, {3 * time.Second, 4 * time.Second}}
+	for _, step := range steps {
+		select {
+		case at := <-dials:
+			if at != step.at {
+				t.Fatalf("dialed after %v, want %v", at, step.at)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("no dial after %v", step.at)
+		}
+		blocked := make(chan struct{})
+		go func() {
+			fake.BlockUntil(1)
+			close(blocked)
+		}()
+		select {
+		case <-blocked:
+		case <-time.After(3 * time.Second):
+			t.Fatal("client redialed without waiting for the backoff")
+		}
+		fake.Advance(step.backoff)
+	}
+}
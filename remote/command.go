@@ -0,0 +1,43 @@
+package remote
+
+import (
+	"go.uber.org/atomic"
+	pb "rocketmq-go/common/proto"
+)
+
+const (
+	rpcType   = 0 // 0 request, 1 response
+	rpcOneway = 1 // 0 rpc, 1 oneway
+)
+
+var requestId atomic.Int32
+
+// NextOpaque returns a request id unique within the process.
+func NextOpaque() int32 {
+	return requestId.Inc()
+}
+
+func MarkResponseType(cmd *pb.RemoteCommand) {
+	cmd.Flag |= 1 << rpcType
+}
+
+func IsResponseType(cmd *pb.RemoteCommand) bool {
+	return cmd.Flag&(1<<rpcType) != 0
+}
+
+func MarkOnewayRPC(cmd *pb.RemoteCommand) {
+	cmd.Flag |= 1 << rpcOneway
+}
+
+func IsOnewayRPC(cmd *pb.RemoteCommand) bool {
+	return cmd.Flag&(1<<rpcOneway) != 0
+}
+
+func CreateResponseCommand(code pb.ResponseCode, remark string) *pb.RemoteCommand {
+	cmd := &pb.RemoteCommand{
+		Code:   int32(code),
+		Remark: remark,
+	}
+	MarkResponseType(cmd)
+	return cmd
+}
@@ -0,0 +1,82 @@
+package remote
+
+import (
+	"sync"
+)
+
+const (
+	defaultExecutorWorkers       = 8
+	defaultExecutorQueueCapacity = 10000
+)
+
+// Executor runs tasks on a fixed number of workers fed by a bounded queue.
+type Executor struct {
+	name  string
+	queue chan func()
+
+	rw     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewExecutor starts workers goroutines, a non positive workers or
+// queueCapacity takes the default.
+func NewExecutor(name string, workers int, queueCapacity int) *Executor {
+	if workers <= 0 {
+		workers = defaultExecutorWorkers
+	}
+	if queueCapacity <= 0 {
+		queueCapacity = defaultExecutorQueueCapacity
+	}
+
+	e := &Executor{
+		name:  name,
+		queue: make(chan func(), queueCapacity),
+	}
+	e.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go e.work()
+	}
+	return e
+}
+
+func (e *Executor) Name() string {
+	return e.name
+}
+
+func (e *Executor) work() {
+	defer e.wg.Done()
+	for task := range e.queue {
+		task()
+	}
+}
+
+// Submit queues task, it returns false without blocking when the queue is
+// full or the executor is shut down.
+func (e *Executor) Submit(task func()) bool {
+	e.rw.RLock()
+	defer e.rw.RUnlock()
+
+	if e.closed {
+		return false
+	}
+
+	select {
+	case e.queue <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+// Shutdown stops taking tasks and waits for the queued ones to finish.
+func (e *Executor) Shutdown() {
+	e.rw.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.queue)
+	}
+	e.rw.Unlock()
+
+	e.wg.Wait()
+}
@@ -0,0 +1,94 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	. "rocketmq-go/common"
+	pb "rocketmq-go/common/proto"
+	. "rocketmq-go/logging"
+	"runtime/debug"
+	"time"
+)
+
+// Interceptor wraps a processor, it may handle the request itself or pass
+// it on to next.
+type Interceptor func(next Processor) Processor
+
+// Chain wraps p with interceptors, the first one is the outermost.
+func Chain(p Processor, interceptors ...Interceptor) Processor {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		p = interceptors[i](p)
+	}
+	return p
+}
+
+// AccessLog logs every request with its response code and cost.
+func AccessLog() Interceptor {
+	return func(next Processor) Processor {
+		return func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+			start := time.Now()
+			response := next(ctx, request)
+
+			fields := []zap.Field{
+				zap.Int32("code", request.Code),
+				zap.String("addr", GetRemoteAddr(ctx)),
+				zap.Int32("opaque", request.Opaque),
+				zap.Duration("cost", time.Since(start)),
+			}
+			if response != nil {
+				fields = append(fields, zap.Int32("responseCode", response.Code))
+			}
+			Log.Debug("Process request", fields...)
+			return response
+		}
+	}
+}
+
+// Recovery turns a panic of the processor into a SYSTEM_ERROR response
+// instead of crashing the server.
+func Recovery() Interceptor {
+	return func(next Processor) Processor {
+		return func(ctx context.Context, request *pb.RemoteCommand) (response *pb.RemoteCommand) {
+			defer func() {
+				if r := recover(); r != nil {
+					Log.Error("Process request panic",
+						zap.Int32("code", request.Code),
+						zap.String("addr", GetRemoteAddr(ctx)),
+						zap.Any("panic", r),
+						zap.ByteString("stack", debug.Stack()))
+					response = CreateResponseCommand(pb.ResponseCode_SYSTEM_ERROR,
+						fmt.Sprintf("process request panic: %v", r))
+				}
+			}()
+			return next(ctx, request)
+		}
+	}
+}
+
+// Latency calls observe with the request, its response and the time its
+// processing took, the response is nil for a oneway request.
+func Latency(observe func(request *pb.RemoteCommand, response *pb.RemoteCommand, cost time.Duration)) Interceptor {
+	return func(next Processor) Processor {
+		return func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+			start := time.Now()
+			response := next(ctx, request)
+			observe(request, response, time.Since(start))
+			return response
+		}
+	}
+}
+
+// Validation rejects the requests whose code is not a known RequestCode
+// before they reach the processor.
+func Validation() Interceptor {
+	return func(next Processor) Processor {
+		return func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+			if _, ok := pb.RequestCode_name[request.Code]; !ok {
+				return CreateResponseCommand(pb.ResponseCode_REQUEST_CODE_NOT_SUPPORTED,
+					fmt.Sprintf("request code %d not supported", request.Code))
+			}
+			return next(ctx, request)
+		}
+	}
+}
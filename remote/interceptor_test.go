@@ -0,0 +1,84 @@
+package remote
+
+import (
+	"context"
+	pb "rocketmq-go/common/proto"
+	"testing"
+	"time"
+)
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+	trace := func(name string) Interceptor {
+		return func(next Processor) Processor {
+			return func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+				order = append(order, name)
+				return next(ctx, request)
+			}
+		}
+	}
+
+	p := Chain(echoProcessor, trace("first"), trace("second"))
+	p(context.Background(), &pb.RemoteCommand{})
+
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Fatalf("order = %v", order)
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	p := Chain(func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		panic("boom")
+	}, Recovery())
+
+	response := p(context.Background(), &pb.RemoteCommand{})
+	if response.Code != int32(pb.ResponseCode_SYSTEM_ERROR) {
+		t.Fatalf("code = %d, want SYSTEM_ERROR", response.Code)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	p := Chain(echoProcessor, Validation())
+
+	response := p(context.Background(), &pb.RemoteCommand{Code: 10000})
+	if response.Code != int32(pb.ResponseCode_REQUEST_CODE_NOT_SUPPORTED) {
+		t.Fatalf("code = %d, want REQUEST_CODE_NOT_SUPPORTED", response.Code)
+	}
+
+	response = p(context.Background(), &pb.RemoteCommand{Code: int32(pb.RequestCode_GET_ROUTEINFO_BY_TOPIC)})
+	if response.Code != int32(pb.ResponseCode_SUCCESS) {
+		t.Fatalf("code = %d, want SUCCESS", response.Code)
+	}
+}
+
+func TestServerUse(t *testing.T) {
+	observed := make(chan int32, 1)
+
+	s := NewServer("127.0.0.1:0")
+	s.RegisterDefaultProcessor(func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		panic("boom")
+	}, nil)
+	s.Use(Latency(func(request *pb.RemoteCommand, response *pb.RemoteCommand, cost time.Duration) {
+		observed <- response.Code
+	}), Recovery())
+	s.Start()
+	defer s.Stop()
+
+	c := NewClient(s.Addr().String())
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	request := &pb.RemoteCommand{Code: int32(pb.RequestCode_GET_KV_CONFIG)}
+	response, err := c.InvokeSync(context.Background(), request, 3*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Code != int32(pb.ResponseCode_SYSTEM_ERROR) {
+		t.Fatalf("code = %d, want SYSTEM_ERROR", response.Code)
+	}
+	if code := <-observed; code != int32(pb.ResponseCode_SYSTEM_ERROR) {
+		t.Fatalf("observed response code = %d", code)
+	}
+}
@@ -0,0 +1,302 @@
+// Package native speaks the remoting protocol of the java RocketMQ, so java
+// brokers and clients can talk to the go name server.
+package native
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+const (
+	SerializeJSON     byte = 0
+	SerializeRocketMQ byte = 1
+
+	// Frames bigger than this are refused, the same as the java side
+	maxFrameLength = 16 * 1024 * 1024
+
+	// Flag bits, the same as RemoteCommand.Flag
+	flagResponse = 1
+	flagOneway   = 1 << 1
+
+	languageGo     = "GO"
+	languageCodeGo = 9
+	defaultVersion = 395
+)
+
+var (
+	ErrFrameTooLarge = errors.New("native: frame too large")
+
+	// LanguageCode of java, ordinal of each name
+	languageCodes = []string{"JAVA", "CPP", "DOTNET", "PYTHON", "DELPHI", "ERLANG", "RUBY", "OTHER", "HTTP", "GO", "PHP", "OMS"}
+)
+
+// Command is a java RemotingCommand.
+type Command struct {
+	Code          int32
+	Language      string
+	Version       int32
+	Opaque        int32
+	Flag          int32
+	Remark        string
+	ExtFields     map[string]string
+	SerializeType byte
+	Body          []byte
+}
+
+func (c *Command) IsResponse() bool {
+	return c.Flag&flagResponse != 0
+}
+
+func (c *Command) IsOneway() bool {
+	return c.Flag&flagOneway != 0
+}
+
+// jsonHeader is the header written by fastjson, fields in name order.
+type jsonHeader struct {
+	Code                    int32             `json:"code"`
+	ExtFields               map[string]string `json:"extFields,omitempty"`
+	Flag                    int32             `json:"flag"`
+	Language                string            `json:"language"`
+	Opaque                  int32             `json:"opaque"`
+	Remark                  string            `json:"remark,omitempty"`
+	SerializeTypeCurrentRPC string            `json:"serializeTypeCurrentRPC"`
+	Version                 int32             `json:"version"`
+}
+
+// ReadCommand reads one frame: a 4 byte length, a 4 byte serialize type and
+// header length, the header and the body.
+func ReadCommand(r io.Reader) (*Command, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(prefix[:])
+	if length > maxFrameLength {
+		return nil, ErrFrameTooLarge
+	}
+	if length < 4 {
+		return nil, fmt.Errorf("native: frame length %d too small", length)
+	}
+
+	frame := make([]byte, length)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return nil, err
+	}
+	return decodeFrame(frame)
+}
+
+func decodeFrame(frame []byte) (*Command, error) {
+	typeAndLength := binary.BigEndian.Uint32(frame[:4])
+	serializeType := byte(typeAndLength >> 24)
+	headerLength := typeAndLength & 0xFFFFFF
+	if int(headerLength) > len(frame)-4 {
+		return nil, fmt.Errorf("native: header length %d exceeds frame", headerLength)
+	}
+	header := frame[4 : 4+headerLength]
+
+	var cmd *Command
+	var err error
+	switch serializeType {
+	case SerializeJSON:
+		cmd, err = decodeJSONHeader(header)
+	case SerializeRocketMQ:
+		cmd, err = decodeRocketMQHeader(header)
+	default:
+		err = fmt.Errorf("native: unknown serialize type %d", serializeType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cmd.SerializeType = serializeType
+	if body := frame[4+headerLength:]; len(body) > 0 {
+		cmd.Body = body
+	}
+	return cmd, nil
+}
+
+func decodeJSONHeader(data []byte) (*Command, error) {
+	var h jsonHeader
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("native: decode json header: %w", err)
+	}
+	return &Command{
+		Code:      h.Code,
+		Language:  h.Language,
+		Version:   h.Version,
+		Opaque:    h.Opaque,
+		Flag:      h.Flag,
+		Remark:    h.Remark,
+		ExtFields: h.ExtFields,
+	}, nil
+}
+
+// headerReader reads the ROCKETMQ binary header, the first error sticks.
+type headerReader struct {
+	data []byte
+	err  error
+}
+
+func (r *headerReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data) {
+		r.err = errors.New("native: binary header truncated")
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *headerReader) int16() int16 {
+	if b := r.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (r *headerReader) int32() int32 {
+	if b := r.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (r *headerReader) byte() byte {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func decodeRocketMQHeader(data []byte) (*Command, error) {
+	r := &headerReader{data: data}
+	cmd := &Command{}
+	cmd.Code = int32(r.int16())
+	language := int(r.byte())
+	cmd.Version = int32(r.int16())
+	cmd.Opaque = r.int32()
+	cmd.Flag = r.int32()
+	if remarkLength := r.int32(); remarkLength > 0 {
+		cmd.Remark = string(r.next(int(remarkLength)))
+	}
+
+	if extLength := r.int32(); extLength > 0 {
+		ext := &headerReader{data: r.next(int(extLength))}
+		cmd.ExtFields = make(map[string]string)
+		for r.err == nil && ext.err == nil && len(ext.data) > 0 {
+			key := string(ext.next(int(ext.int16())))
+			value := string(ext.next(int(ext.int32())))
+			cmd.ExtFields[key] = value
+		}
+		if ext.err != nil {
+			return nil, ext.err
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if language < len(languageCodes) {
+		cmd.Language = languageCodes[language]
+	}
+	return cmd, nil
+}
+
+// Encode returns the whole frame of c, the header in c.SerializeType.
+func (c *Command) Encode() ([]byte, error) {
+	var header []byte
+	switch c.SerializeType {
+	case SerializeJSON:
+		data, err := json.Marshal(jsonHeader{
+			Code:                    c.Code,
+			ExtFields:               c.ExtFields,
+			Flag:                    c.Flag,
+			Language:                c.language(),
+			Opaque:                  c.Opaque,
+			Remark:                  c.Remark,
+			SerializeTypeCurrentRPC: "JSON",
+			Version:                 c.version(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		header = data
+	case SerializeRocketMQ:
+		header = c.encodeRocketMQHeader()
+	default:
+		return nil, fmt.Errorf("native: unknown serialize type %d", c.SerializeType)
+	}
+
+	length := 4 + len(header) + len(c.Body)
+	if length > maxFrameLength {
+		return nil, ErrFrameTooLarge
+	}
+
+	frame := make([]byte, 8, 4+length)
+	binary.BigEndian.PutUint32(frame[:4], uint32(length))
+	binary.BigEndian.PutUint32(frame[4:8], uint32(c.SerializeType)<<24|uint32(len(header)))
+	frame = append(frame, header...)
+	frame = append(frame, c.Body...)
+	return frame, nil
+}
+
+func (c *Command) language() string {
+	if c.Language == "" {
+		return languageGo
+	}
+	return c.Language
+}
+
+func (c *Command) version() int32 {
+	if c.Version == 0 {
+		return defaultVersion
+	}
+	return c.Version
+}
+
+func (c *Command) encodeRocketMQHeader() []byte {
+	var buf bytes.Buffer
+	putInt16 := func(v int16) { _ = binary.Write(&buf, binary.BigEndian, v) }
+	putInt32 := func(v int32) { _ = binary.Write(&buf, binary.BigEndian, v) }
+
+	language := byte(languageCodeGo)
+	for i, name := range languageCodes {
+		if name == c.language() {
+			language = byte(i)
+		}
+	}
+
+	putInt16(int16(c.Code))
+	buf.WriteByte(language)
+	putInt16(int16(c.version()))
+	putInt32(c.Opaque)
+	putInt32(c.Flag)
+	putInt32(int32(len(c.Remark)))
+	buf.WriteString(c.Remark)
+
+	keys := make([]string, 0, len(c.ExtFields))
+	for key := range c.ExtFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var ext bytes.Buffer
+	for _, key := range keys {
+		value := c.ExtFields[key]
+		_ = binary.Write(&ext, binary.BigEndian, int16(len(key)))
+		ext.WriteString(key)
+		_ = binary.Write(&ext, binary.BigEndian, int32(len(value)))
+		ext.WriteString(value)
+	}
+	putInt32(int32(ext.Len()))
+	buf.Write(ext.Bytes())
+	return buf.Bytes()
+}
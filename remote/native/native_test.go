@@ -0,0 +1,261 @@
+package native
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	. "rocketmq-go/common"
+	pb "rocketmq-go/common/proto"
+	"rocketmq-go/remote"
+	"testing"
+	"time"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	for _, serializeType := range []byte{SerializeJSON, SerializeRocketMQ} {
+		cmd := &Command{
+			Code:          105,
+			Language:      "JAVA",
+			Version:       395,
+			Opaque:        42,
+			Flag:          flagOneway,
+			Remark:        "remark",
+			ExtFields:     map[string]string{"topic": "TopicTest"},
+			SerializeType: serializeType,
+			Body:          []byte("body"),
+		}
+		frame, err := cmd.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, err := ReadCommand(bytes.NewReader(frame))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Code != cmd.Code || decoded.Language != cmd.Language || decoded.Version != cmd.Version ||
+			decoded.Opaque != cmd.Opaque || decoded.Flag != cmd.Flag || decoded.Remark != cmd.Remark ||
+			decoded.ExtFields["topic"] != "TopicTest" || string(decoded.Body) != "body" ||
+			decoded.SerializeType != serializeType {
+			t.Errorf("serialize type %d: decoded %+v, want %+v", serializeType, decoded, cmd)
+		}
+	}
+}
+
+func TestDecodeJavaFrame(t *testing.T) {
+	// GET_ROUTEINFO_BY_TOPIC as written by a java client
+	header := []byte(`{"code":105,"extFields":{"topic":"TopicTest"},"flag":0,"language":"JAVA",` +
+		`"opaque":7,"serializeTypeCurrentRPC":"JSON","version":395}`)
+	frame := make([]byte, 8)
+	binary.BigEndian.PutUint32(frame[:4], uint32(4+len(header)))
+	binary.BigEndian.PutUint32(frame[4:], uint32(len(header)))
+	frame = append(frame, header...)
+
+	cmd, err := ReadCommand(bytes.NewReader(frame))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Code != 105 || cmd.Opaque != 7 || cmd.ExtFields["topic"] != "TopicTest" || cmd.Body != nil {
+		t.Fatalf("decoded %+v", cmd)
+	}
+}
+
+func startNativeServer(t *testing.T, processor remote.Processor) net.Conn {
+	remoteSrv := remote.NewServer("127.0.0.1:0")
+	remoteSrv.RegisterDefaultProcessor(processor, nil)
+	remoteSrv.Use(remote.Validation(), Translate())
+	remoteSrv.Start()
+	t.Cleanup(remoteSrv.Stop)
+
+	s := NewServer("127.0.0.1:0", remoteSrv)
+	s.Start()
+	t.Cleanup(s.Stop)
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func invoke(t *testing.T, conn net.Conn, cmd *Command) *Command {
+	frame, err := cmd.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	response, err := ReadCommand(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.IsResponse() || response.Opaque != cmd.Opaque {
+		t.Fatalf("response %+v does not answer request %d", response, cmd.Opaque)
+	}
+	return response
+}
+
+func kvProcessor(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+	header := &pb.GetKVConfigRequestHeader{}
+	if err := Deserializable(request.Header, header, false); err != nil {
+		return remote.CreateResponseCommand(pb.ResponseCode_HEADER_DECODE_ERROR, err.Error())
+	}
+	if header.Namespace != "ORDER_TOPIC_CONFIG" || header.Key != "TopicTest" {
+		return remote.CreateResponseCommand(pb.ResponseCode_QUERY_NOT_FOUND, "no config item")
+	}
+
+	response := remote.CreateResponseCommand(pb.ResponseCode_SUCCESS, "")
+	response.Header = Serializable(&pb.GetKVConfigResponseHeader{Value: "broker-a:4"})
+	return response
+}
+
+func TestNativeRequest(t *testing.T) {
+	conn := startNativeServer(t, kvProcessor)
+
+	for _, serializeType := range []byte{SerializeJSON, SerializeRocketMQ} {
+		response := invoke(t, conn, &Command{
+			Code:          101,
+			Opaque:        1,
+			ExtFields:     map[string]string{"namespace": "ORDER_TOPIC_CONFIG", "key": "TopicTest"},
+			SerializeType: serializeType,
+		})
+		if response.Code != 0 || response.ExtFields["value"] != "broker-a:4" {
+			t.Errorf("serialize type %d: response %+v", serializeType, response)
+		}
+		if response.SerializeType != serializeType {
+			t.Errorf("response serialize type = %d, want %d", response.SerializeType, serializeType)
+		}
+	}
+
+	response := invoke(t, conn, &Command{
+		Code:      101,
+		Opaque:    2,
+		ExtFields: map[string]string{"namespace": "ORDER_TOPIC_CONFIG", "key": "TopicOther"},
+	})
+	if response.Code != 22 {
+		t.Errorf("QUERY_NOT_FOUND answered as code %d, want 22", response.Code)
+	}
+
+	response = invoke(t, conn, &Command{Code: 9999, Opaque: 3})
+	if response.Code != int32(pb.ResponseCode_REQUEST_CODE_NOT_SUPPORTED) {
+		t.Errorf("unknown code answered as code %d", response.Code)
+	}
+}
+
+func TestTranslateBodies(t *testing.T) {
+	received := make(chan *pb.RegisterBrokerBody, 1)
+	conn := startNativeServer(t, func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		switch pb.RequestCode(request.Code) {
+		case pb.RequestCode_REGISTER_BROKER:
+			body := &pb.RegisterBrokerBody{}
+			if err := Deserializable(request.Body, body, false); err != nil {
+				return remote.CreateResponseCommand(pb.ResponseCode_SYSTEM_ERROR, err.Error())
+			}
+			received <- body
+			return remote.CreateResponseCommand(pb.ResponseCode_SUCCESS, "")
+		default:
+			response := remote.CreateResponseCommand(pb.ResponseCode_SUCCESS, "")
+			response.Header = Serializable(&pb.QueryDataVersionResponseHeader{})
+			response.Body = Serializable(&pb.DataVersion{Timestamp: 1600000000000, Counter: 3})
+			return response
+		}
+	})
+
+	body := `{"filterServerList":[],"topicConfigSerializeWrapper":{"dataVersion":{"counter":2,` +
+		`"timestamp":1600000000000},"topicConfigTable":{"TopicTest":{"order":false,"perm":6,` +
+		`"readQueueNums":8,"topicFilterType":"SINGLE_TAG","topicName":"TopicTest","topicSysFlag":0,` +
+		`"writeQueueNums":8}}}}`
+	response := invoke(t, conn, &Command{
+		Code:   103,
+		Opaque: 1,
+		ExtFields: map[string]string{
+			"brokerName": "broker-a", "brokerAddr": "127.0.0.1:10911", "clusterName": "DefaultCluster",
+			"haServerAddr": "127.0.0.1:10912", "brokerId": "0", "compressed": "false", "bodyCrc32": "-12345",
+		},
+		Body: []byte(body),
+	})
+	if response.Code != 0 {
+		t.Fatalf("register broker: code %d, remark %s", response.Code, response.Remark)
+	}
+	registered := <-received
+	topicConfig := registered.TopicConfigSerializeWrapper.TopicConfigTable["TopicTest"]
+	if topicConfig == nil || topicConfig.WriteQueueNums != 8 || topicConfig.Perm != 6 ||
+		registered.TopicConfigSerializeWrapper.DataVersion.Counter != 2 {
+		t.Fatalf("register broker body %v", registered)
+	}
+
+	response = invoke(t, conn, &Command{
+		Code:      322,
+		Opaque:    2,
+		ExtFields: map[string]string{"brokerAddr": "127.0.0.1:10911"},
+		Body:      []byte(`{"counter":2,"timestamp":1600000000000}`),
+	})
+	if response.ExtFields["changed"] != "false" {
+		t.Errorf("changed = %q, want false", response.ExtFields["changed"])
+	}
+	if string(response.Body) != `{"timestamp":1600000000000,"counter":3}` {
+		t.Errorf("data version body = %s", response.Body)
+	}
+}
+
+func TestShutdownAnswersInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	remoteSrv := remote.NewServer("127.0.0.1:0")
+	remoteSrv.RegisterDefaultProcessor(func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		close(started)
+		<-release
+		return kvProcessor(ctx, request)
+	}, nil)
+	remoteSrv.Use(remote.Validation(), Translate())
+	remoteSrv.Start()
+	defer remoteSrv.Stop()
+
+	s := NewServer("127.0.0.1:0", remoteSrv)
+	s.Start()
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	frame, _ := (&Command{
+		Code:      101,
+		Opaque:    1,
+		ExtFields: map[string]string{"namespace": "ORDER_TOPIC_CONFIG", "key": "TopicTest"},
+	}).Encode()
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownErr <- s.Shutdown(ctx)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	response, err := ReadCommand(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Code != 0 || response.ExtFields["value"] != "broker-a:4" {
+		t.Errorf("response %+v", response)
+	}
+	if err := <-shutdownErr; err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if _, err := ReadCommand(conn); err == nil {
+		t.Error("connection still open after shutdown")
+	}
+}
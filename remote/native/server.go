@@ -0,0 +1,303 @@
+package native
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/stats"
+	"io"
+	"net"
+	pb "rocketmq-go/common/proto"
+	. "rocketmq-go/logging"
+	"rocketmq-go/remote"
+	"sync"
+	"time"
+)
+
+// Server listens for java remoting connections and dispatches their
+// requests into the processors, executors and interceptors of a
+// remote.Server, which must be started first.
+type Server struct {
+	addr      string
+	remoteSrv *remote.Server
+	listener  net.Listener
+
+	rw     sync.Mutex
+	closed bool
+	conns  map[net.Conn]bool
+	wg     sync.WaitGroup
+}
+
+func NewServer(addr string, remoteSrv *remote.Server) *Server {
+	return &Server{
+		addr:      addr,
+		remoteSrv: remoteSrv,
+		conns:     make(map[net.Conn]bool),
+	}
+}
+
+func (s *Server) Start() error {
+	listen, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listen
+
+	s.wg.Add(1)
+	go s.accept()
+	Log.Info("Native remoting server started", zap.String("addr", listen.Addr().String()))
+	return nil
+}
+
+// Addr returns the address the server listens on, it is only valid after
+// Start.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Stop closes the listener and every connection, then waits for the
+// requests in flight.
+func (s *Server) Stop() {
+	s.rw.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.rw.Unlock()
+
+	_ = s.listener.Close()
+	s.wg.Wait()
+	Log.Info("Native remoting server stopped")
+}
+
+// Shutdown stops reading requests and waits for the ones in flight to be
+// answered before closing the connections. When ctx is done first, the
+// connections left are closed like Stop and ctx.Err() is returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.rw.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		// Wakes up the reads, serve stops once it sees closed
+		_ = conn.SetReadDeadline(time.Now())
+	}
+	s.rw.Unlock()
+	_ = s.listener.Close()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		Log.Info("Native remoting server stopped")
+		return nil
+	case <-ctx.Done():
+		Log.Warn("Native remoting server shutdown timeout, close the connections left", zap.Error(ctx.Err()))
+		s.Stop()
+		return ctx.Err()
+	}
+}
+
+// ActiveConnections returns the number of remoting connections open.
+func (s *Server) ActiveConnections() int64 {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	return int64(len(s.conns))
+}
+
+func (s *Server) isClosed() bool {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	return s.closed
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !s.isClosed() {
+				Log.Error("Native remoting server exit", zap.Error(err))
+			}
+			return
+		}
+
+		s.rw.Lock()
+		if s.closed {
+			s.rw.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = true
+		s.wg.Add(1)
+		s.rw.Unlock()
+
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	addr := conn.RemoteAddr().String()
+	ch := newConnChannel(conn)
+	listener := s.remoteSrv.ChannelEventListener
+	if listener != nil {
+		listener.OnChannelConnect(addr)
+	}
+
+	// The processors find the remote address the same way as for gRPC
+	ctx := context.WithValue(context.Background(), "conn", &stats.ConnTagInfo{
+		RemoteAddr: conn.RemoteAddr(),
+		LocalAddr:  conn.LocalAddr(),
+	})
+	ctx, cancel := context.WithCancel(remote.WithChannel(withNative(ctx), ch))
+
+	var inFlight sync.WaitGroup
+	defer func() {
+		cancel()
+		inFlight.Wait()
+		ch.close()
+
+		s.rw.Lock()
+		delete(s.conns, conn)
+		s.rw.Unlock()
+		if listener != nil {
+			listener.OnChannelClose(addr)
+		}
+		s.wg.Done()
+	}()
+
+	idleTime := s.remoteSrv.ChannelMaxIdleTime
+	reader := bufio.NewReader(conn)
+	for {
+		// Under the lock, or the deadline set by Shutdown could be lost
+		s.rw.Lock()
+		closed := s.closed
+		if !closed && idleTime > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(idleTime))
+		}
+		s.rw.Unlock()
+		if closed {
+			return
+		}
+
+		cmd, err := ReadCommand(reader)
+		if err != nil {
+			s.readError(addr, err)
+			return
+		}
+		if cmd.IsResponse() {
+			Log.Warn("Receive response, no request waiting for it",
+				zap.String("addr", addr),
+				zap.Int32("opaque", cmd.Opaque))
+			continue
+		}
+
+		ch.serializeType.Store(int32(cmd.SerializeType))
+		req, ok := ToRemote(cmd)
+		if !ok {
+			Log.Warn("Receive request, unknown java code", zap.String("addr", addr), zap.Int32("code", cmd.Code))
+			if cmd.IsOneway() {
+				continue
+			}
+			response := &Command{
+				Code:          int32(pb.ResponseCode_REQUEST_CODE_NOT_SUPPORTED),
+				Opaque:        cmd.Opaque,
+				Flag:          flagResponse,
+				Remark:        "request code not supported",
+				SerializeType: cmd.SerializeType,
+			}
+			if err := ch.write(response); err != nil {
+				s.readError(addr, err)
+				return
+			}
+			continue
+		}
+
+		if err := s.remoteSrv.Dispatch(ctx, ch, req, &inFlight); err != nil {
+			s.readError(addr, err)
+			return
+		}
+	}
+}
+
+func (s *Server) readError(addr string, err error) {
+	// Reads fail on purpose once the server is closed
+	if s.isClosed() {
+		return
+	}
+	listener := s.remoteSrv.ChannelEventListener
+
+	var netErr net.Error
+	switch {
+	case err == io.EOF:
+	case errors.As(err, &netErr) && netErr.Timeout():
+		Log.Warn("Channel idle, close it", zap.String("addr", addr))
+		if listener != nil {
+			listener.OnChannelIdle(addr)
+		}
+	default:
+		Log.Warn("Channel exception", zap.String("addr", addr), zap.Error(err))
+		if listener != nil {
+			listener.OnChannelException(addr, err)
+		}
+	}
+}
+
+// connChannel writes our commands to a java connection, responses take
+// the serialize type of the last request.
+type connChannel struct {
+	conn          net.Conn
+	addr          string
+	serializeType atomic.Int32
+
+	mu        sync.Mutex
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func newConnChannel(conn net.Conn) *connChannel {
+	return &connChannel{
+		conn: conn,
+		addr: conn.RemoteAddr().String(),
+		done: make(chan struct{}),
+	}
+}
+
+func (c *connChannel) RemoteAddr() string {
+	return c.addr
+}
+
+func (c *connChannel) Send(cmd *pb.RemoteCommand) error {
+	nativeCmd, err := FromRemote(cmd, byte(c.serializeType.Load()))
+	if err != nil {
+		return err
+	}
+	return c.write(nativeCmd)
+}
+
+func (c *connChannel) write(cmd *Command) error {
+	frame, err := cmd.Encode()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err = c.conn.Write(frame)
+	return err
+}
+
+func (c *connChannel) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *connChannel) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.conn.Close()
+	})
+}
@@ -0,0 +1,355 @@
+package native
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	. "rocketmq-go/common"
+	pb "rocketmq-go/common/proto"
+	"rocketmq-go/remote"
+	"strconv"
+)
+
+// codeMapping ties a java request code to ours. The headers are carried as
+// extFields by java, the prototypes tell which message they decode into.
+type codeMapping struct {
+	javaCode       int32
+	code           pb.RequestCode
+	requestHeader  proto.Message
+	responseHeader proto.Message
+	// requestBody and responseBody convert the bodies which are json in
+	// java and protobuf here, nil when the body is the same on both sides.
+	requestBody  func(ext map[string]string, body []byte) ([]byte, error)
+	responseBody func(body []byte) ([]byte, error)
+}
+
+var (
+	codeMappings = []codeMapping{
+		{javaCode: 100, code: pb.RequestCode_PUT_KV_CONFIG, requestHeader: &pb.PutKVConfigRequestHeader{}},
+		{javaCode: 101, code: pb.RequestCode_GET_KV_CONFIG, requestHeader: &pb.GetKVConfigRequestHeader{},
+			responseHeader: &pb.GetKVConfigResponseHeader{}},
+		{javaCode: 102, code: pb.RequestCode_DELETE_KV_CONFIG, requestHeader: &pb.DeleteKVConfigRequestHeader{}},
+		{javaCode: 103, code: pb.RequestCode_REGISTER_BROKER, requestHeader: &pb.RegisterBrokerRequestHeader{},
+			responseHeader: &pb.RegisterBrokerResponseHeader{}, requestBody: registerBrokerBody},
+		{javaCode: 104, code: pb.RequestCode_UNREGISTER_BROKER, requestHeader: &pb.UnRegisterBrokerHeader{}},
+		{javaCode: 105, code: pb.RequestCode_GET_ROUTEINFO_BY_TOPIC, requestHeader: &pb.GetRouteInfoRequestHeader{}},
+		{javaCode: 106, code: pb.RequestCode_GET_BROKER_CLUSTER_INFO},
+		{javaCode: 205, code: pb.RequestCode_WIPE_WRITE_PERM_OF_BROKER, requestHeader: &pb.WipeWritePermOfBrokerRequestHeader{},
+			responseHeader: &pb.WipeWritePermOfBrokerResponseHeader{}},
+		{javaCode: 206, code: pb.RequestCode_GET_ALL_TOPIC_LIST_FROM_NAMESERVER},
+		{javaCode: 216, code: pb.RequestCode_DELETE_TOPIC_IN_NAMESRV, requestHeader: &pb.DeleteTopicInNamesrvRequestHeader{}},
+		{javaCode: 219, code: pb.RequestCode_GET_KVLIST_BY_NAMESPACE, requestHeader: &pb.GetKVListByNamespaceRequestHeader{}},
+		{javaCode: 224, code: pb.RequestCode_GET_TOPICS_BY_CLUSTER, requestHeader: &pb.GetTopicsByClusterRequestHeader{}},
+		{javaCode: 304, code: pb.RequestCode_GET_SYSTEM_TOPIC_LIST_FROM_NS},
+		{javaCode: 311, code: pb.RequestCode_GET_UNIT_TOPIC_LIST},
+		{javaCode: 312, code: pb.RequestCode_GET_HAS_UNIT_SUB_TOPIC_LIST},
+		{javaCode: 313, code: pb.RequestCode_GET_HAS_UNIT_SUB_UNUNIT_TOPIC_LIST},
+		{javaCode: 318, code: pb.RequestCode_UPDATE_NAMESRV_CONFIG},
+		{javaCode: 319, code: pb.RequestCode_GET_NAMESRV_CONFIG},
+		{javaCode: 322, code: pb.RequestCode_QUERY_DATA_VERSION, requestHeader: &pb.QueryDataVersionRequestHeader{},
+			responseHeader: &pb.QueryDataVersionResponseHeader{}, requestBody: dataVersionRequestBody,
+			responseBody: dataVersionResponseBody},
+		{javaCode: 327, code: pb.RequestCode_ADD_WRITE_PERM_OF_BROKER, requestHeader: &pb.AddWritePermOfBrokerRequestHeader{},
+			responseHeader: &pb.AddWritePermOfBrokerResponseHeader{}},
+	}
+
+	byJavaCode = make(map[int32]*codeMapping)
+	byCode     = make(map[int32]*codeMapping)
+
+	// Response codes whose number differs in java, or which java does not
+	// have and falls back to the nearest one
+	responseCodes = map[pb.ResponseCode]int32{
+		pb.ResponseCode_TOPIC_NOT_EXIST:     17,
+		pb.ResponseCode_QUERY_NOT_FOUND:     22,
+		pb.ResponseCode_NO_PERMISSION:       16,
+		pb.ResponseCode_HEADER_DECODE_ERROR: int32(pb.ResponseCode_SYSTEM_ERROR),
+		pb.ResponseCode_ACCESS_DENIED:       16,
+	}
+	javaResponseCodes = map[int32]pb.ResponseCode{
+		17: pb.ResponseCode_TOPIC_NOT_EXIST,
+		22: pb.ResponseCode_QUERY_NOT_FOUND,
+		16: pb.ResponseCode_NO_PERMISSION,
+	}
+
+	errNoJavaCode = errors.New("native: request code has no java equivalent")
+)
+
+func init() {
+	for i := range codeMappings {
+		m := &codeMappings[i]
+		byJavaCode[m.javaCode] = m
+		byCode[int32(m.code)] = m
+	}
+}
+
+type nativeKey struct{}
+
+func withNative(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nativeKey{}, true)
+}
+
+// IsNative tells whether the request of ctx came in on the native listener.
+func IsNative(ctx context.Context) bool {
+	native, _ := ctx.Value(nativeKey{}).(bool)
+	return native
+}
+
+// ToRemote turns a java command into ours with the java extFields and body
+// kept as they are, Translate converts them once the request passed the
+// interceptors, so the ACL signature is checked on what the client signed.
+// ok is false for a request code we do not know.
+func ToRemote(cmd *Command) (*pb.RemoteCommand, bool) {
+	remoteCmd := &pb.RemoteCommand{
+		Version:   cmd.Version,
+		Remark:    cmd.Remark,
+		Opaque:    cmd.Opaque,
+		Flag:      cmd.Flag,
+		ExtFields: cmd.ExtFields,
+		Body:      cmd.Body,
+	}
+
+	if cmd.IsResponse() {
+		remoteCmd.Code = int32(pb.ResponseCode_SYSTEM_ERROR)
+		if code, ok := javaResponseCodes[cmd.Code]; ok {
+			remoteCmd.Code = int32(code)
+		} else if _, ok := pb.ResponseCode_name[cmd.Code]; ok {
+			remoteCmd.Code = cmd.Code
+		}
+		return remoteCmd, true
+	}
+
+	m, ok := byJavaCode[cmd.Code]
+	if !ok {
+		return nil, false
+	}
+	remoteCmd.Code = int32(m.code)
+	return remoteCmd, true
+}
+
+// FromRemote turns our command into a java one, the header must already be
+// in ExtFields.
+func FromRemote(cmd *pb.RemoteCommand, serializeType byte) (*Command, error) {
+	nativeCmd := &Command{
+		Code:          cmd.Code,
+		Opaque:        cmd.Opaque,
+		Flag:          cmd.Flag,
+		Remark:        cmd.Remark,
+		ExtFields:     cmd.ExtFields,
+		SerializeType: serializeType,
+		Body:          cmd.Body,
+	}
+
+	if remote.IsResponseType(cmd) {
+		if code, ok := responseCodes[pb.ResponseCode(cmd.Code)]; ok {
+			nativeCmd.Code = code
+		}
+		return nativeCmd, nil
+	}
+
+	m, ok := byCode[cmd.Code]
+	if !ok {
+		return nil, errNoJavaCode
+	}
+	nativeCmd.Code = m.javaCode
+	return nativeCmd, nil
+}
+
+// Translate converts the extFields and json bodies of the requests coming
+// in on the native listener to the protobuf headers and bodies the
+// processors expect, and their responses back. Other requests pass as they
+// are. It must be the innermost interceptor.
+func Translate() remote.Interceptor {
+	return func(next remote.Processor) remote.Processor {
+		return func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+			if !IsNative(ctx) {
+				return next(ctx, request)
+			}
+
+			m, ok := byCode[request.Code]
+			if !ok {
+				return next(ctx, request)
+			}
+
+			translated := &pb.RemoteCommand{
+				Version: request.Version,
+				Code:    request.Code,
+				Remark:  request.Remark,
+				Opaque:  request.Opaque,
+				Flag:    request.Flag,
+				Body:    request.Body,
+			}
+			if m.requestHeader != nil {
+				header := m.requestHeader.ProtoReflect().New().Interface()
+				if err := headerFromExtFields(request.ExtFields, header); err != nil {
+					return remote.CreateResponseCommand(pb.ResponseCode_HEADER_DECODE_ERROR,
+						"decode request header failed: "+err.Error())
+				}
+				translated.Header, _ = proto.Marshal(header)
+			}
+			if m.requestBody != nil && len(request.Body) > 0 {
+				body, err := m.requestBody(request.ExtFields, request.Body)
+				if err != nil {
+					return remote.CreateResponseCommand(pb.ResponseCode_SYSTEM_ERROR,
+						"decode request body failed: "+err.Error())
+				}
+				translated.Body = body
+			}
+
+			response := next(ctx, translated)
+			if response == nil {
+				return nil
+			}
+
+			if m.responseHeader != nil && response.Code == int32(pb.ResponseCode_SUCCESS) {
+				header := m.responseHeader.ProtoReflect().New().Interface()
+				if err := proto.Unmarshal(response.Header, header); err != nil {
+					return remote.CreateResponseCommand(pb.ResponseCode_SYSTEM_ERROR,
+						"encode response header failed: "+err.Error())
+				}
+				if response.ExtFields == nil {
+					response.ExtFields = make(map[string]string)
+				}
+				for key, value := range extFieldsFromHeader(header) {
+					response.ExtFields[key] = value
+				}
+			}
+			response.Header = nil
+
+			if m.responseBody != nil && len(response.Body) > 0 {
+				body, err := m.responseBody(response.Body)
+				if err != nil {
+					return remote.CreateResponseCommand(pb.ResponseCode_SYSTEM_ERROR,
+						"encode response body failed: "+err.Error())
+				}
+				response.Body = body
+			}
+			return response
+		}
+	}
+}
+
+func headerFromExtFields(ext map[string]string, header proto.Message) error {
+	r := header.ProtoReflect()
+	fields := r.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		s, ok := ext[string(fd.Name())]
+		if !ok {
+			continue
+		}
+
+		v, err := parseField(fd, s)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fd.Name(), err)
+		}
+		r.Set(fd, v)
+	}
+	return nil
+}
+
+func parseField(fd protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	if fd.IsList() || fd.IsMap() {
+		return protoreflect.Value{}, fmt.Errorf("unsupported field type")
+	}
+
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(b), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		i, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(i)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		i, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(i), err
+	default:
+		return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s", fd.Kind())
+	}
+}
+
+// extFieldsFromHeader writes zero values too, java reads the missing
+// fields as null.
+func extFieldsFromHeader(header proto.Message) map[string]string {
+	r := header.ProtoReflect()
+	fields := r.Descriptor().Fields()
+	ext := make(map[string]string, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.IsList() || fd.IsMap() || fd.Kind() == protoreflect.MessageKind {
+			continue
+		}
+		ext[string(fd.Name())] = fmt.Sprint(r.Get(fd).Interface())
+	}
+	return ext
+}
+
+type topicConfigJSON struct {
+	TopicName      string `json:"topicName"`
+	ReadQueueNums  int32  `json:"readQueueNums"`
+	WriteQueueNums int32  `json:"writeQueueNums"`
+	Perm           int32  `json:"perm"`
+	TopicSysFlag   int32  `json:"topicSysFlag"`
+	Order          bool   `json:"order"`
+}
+
+type registerBrokerBodyJSON struct {
+	FilterServerList            []string `json:"filterServerList"`
+	TopicConfigSerializeWrapper struct {
+		TopicConfigTable map[string]topicConfigJSON `json:"topicConfigTable"`
+		DataVersion      *DataVersion               `json:"dataVersion"`
+	} `json:"topicConfigSerializeWrapper"`
+}
+
+func registerBrokerBody(ext map[string]string, body []byte) ([]byte, error) {
+	if compressed, _ := strconv.ParseBool(ext["compressed"]); compressed {
+		return nil, errors.New("compressed register body not supported, set compressedRegister=false on the broker")
+	}
+
+	var j registerBrokerBodyJSON
+	if err := json.Unmarshal(body, &j); err != nil {
+		return nil, err
+	}
+
+	wrapper := &pb.TopicConfigSerializeWrapper{
+		TopicConfigTable: make(map[string]*pb.TopicConfig, len(j.TopicConfigSerializeWrapper.TopicConfigTable)),
+	}
+	for name, c := range j.TopicConfigSerializeWrapper.TopicConfigTable {
+		wrapper.TopicConfigTable[name] = &pb.TopicConfig{
+			TopicName:      c.TopicName,
+			ReadQueueNums:  c.ReadQueueNums,
+			WriteQueueNums: c.WriteQueueNums,
+			Perm:           c.Perm,
+			TopicSysFlag:   c.TopicSysFlag,
+			Order:          c.Order,
+		}
+	}
+	if j.TopicConfigSerializeWrapper.DataVersion != nil {
+		wrapper.DataVersion = j.TopicConfigSerializeWrapper.DataVersion.ToProto()
+	}
+
+	return Serializable(&pb.RegisterBrokerBody{
+		FilterServerList:            j.FilterServerList,
+		TopicConfigSerializeWrapper: wrapper,
+	}), nil
+}
+
+func dataVersionRequestBody(ext map[string]string, body []byte) ([]byte, error) {
+	dataVersion := &DataVersion{}
+	if err := json.Unmarshal(body, dataVersion); err != nil {
+		return nil, err
+	}
+	return Serializable(dataVersion.ToProto()), nil
+}
+
+func dataVersionResponseBody(body []byte) ([]byte, error) {
+	v := &pb.DataVersion{}
+	if err := Deserializable(body, v, false); err != nil {
+		return nil, err
+	}
+	return json.Marshal(NewDataVersionFromProto(v))
+}
@@ -2,77 +2,369 @@ package remote
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	//"github.com/golang/protobuf/proto"
 	//"go.uber.org/zap"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
 	"io"
 	"net"
+	. "rocketmq-go/common"
 	pb "rocketmq-go/common/proto"
 	. "rocketmq-go/logging"
+	"sync"
+	"time"
+)
+
+const (
+	defaultChannelMaxIdleTime = 120 * time.Second
 )
 
 type Processor func(context.Context, *pb.RemoteCommand) *pb.RemoteCommand
 
+// processorPair is a processor and the executor its requests run on.
+type processorPair struct {
+	processor Processor
+	executor *Executor
+}
+
 type Server struct {
-	Processor
+	ChannelEventListener ChannelEventListener
+
+	// ChannelMaxIdleTime closes streams neither read nor written for that
+	// long, zero disables the check.
+	ChannelMaxIdleTime time.Duration
+	// TLS serves over TLS when set, it must be set before Start.
+	TLS *TLSConfig
+	// Listener is served instead of listening on the address when set, e.g.
+	// an in-memory one. It must be set before Start.
+	Listener net.Listener
 
 	addr string
+	interceptors []Interceptor
+	defaultProcessor processorPair
+	processorTable map[int32]processorPair
+	listener net.Listener
+	certReloader *certReloader
+	activeConns atomic.Int64
+	shutdownOnce sync.Once
+	shutdownChan chan struct{}
 	srv *grpc.Server
 	pb *pb.UnimplementedRemoteRPCServer
 }
 
 func NewServer(addr string) *Server {
 	return &Server{
+		ChannelMaxIdleTime: defaultChannelMaxIdleTime,
 		addr: addr,
+		processorTable: make(map[int32]processorPair),
+		shutdownChan: make(chan struct{}),
 	}
 }
 
-func (s *Server) Start() {
-	listen, err := net.Listen("tcp", s.addr)
-	if err != nil {
-		Log.Sugar().Fatalf("Failed to listen: %v", err)
+// RegisterProcessor handles the requests of code with processor on
+// executor, a nil executor runs them on the default processor's one.
+// Processors must be registered before Start.
+func (s *Server) RegisterProcessor(code pb.RequestCode, processor Processor, executor *Executor) {
+	s.processorTable[int32(code)] = processorPair{processor, executor}
+}
+
+// RegisterDefaultProcessor handles the requests of the codes without a
+// processor of their own, a nil executor takes a default sized one.
+func (s *Server) RegisterDefaultProcessor(processor Processor, executor *Executor) {
+	if executor == nil {
+		executor = NewExecutor("RemoteExecutor", 0, 0)
+	}
+	s.defaultProcessor = processorPair{processor, executor}
+}
+
+// Use appends interceptors wrapping every processor, the first one added is
+// the outermost. Interceptors must be added before Start.
+func (s *Server) Use(interceptors ...Interceptor) {
+	s.interceptors = append(s.interceptors, interceptors...)
+}
+
+func (s *Server) applyInterceptors() {
+	if s.defaultProcessor.processor != nil {
+		s.defaultProcessor.processor = Chain(s.defaultProcessor.processor, s.interceptors...)
+	}
+	for code, pair := range s.processorTable {
+		if pair.processor == nil {
+			continue
+		}
+		pair.processor = Chain(pair.processor, s.interceptors...)
+		s.processorTable[code] = pair
+	}
+}
+
+func (s *Server) processorOf(code int32) processorPair {
+	pair, ok := s.processorTable[code]
+	if !ok {
+		return s.defaultProcessor
+	}
+	if pair.executor == nil {
+		pair.executor = s.defaultProcessor.executor
 	}
+	return pair
+}
+
+// Start listens and serves in the background. A server failing to start
+// shuts down the executors of its processors.
+func (s *Server) Start() error {
+	s.applyInterceptors()
+
+	listen := s.Listener
+	if listen == nil {
+		var err error
+		if listen, err = net.Listen("tcp", s.addr); err != nil {
+			s.shutdownExecutors()
+			return err
+		}
+	}
+	s.listener = listen
 
 	var opts []grpc.ServerOption
 	//opts = append(opts, grpc.UnaryInterceptor(interceptor))
 	opts = append(opts, grpc.StatsHandler(s))
+	if s.TLS != nil {
+		reloader, err := newCertReloader(s.TLS)
+		if err != nil {
+			_ = listen.Close()
+			s.shutdownExecutors()
+			return fmt.Errorf("load tls certificates: %v", err)
+		}
+		s.certReloader = reloader
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.serverConfig())))
+	}
 
 	s.srv = grpc.NewServer(opts...)
 	pb.RegisterRemoteRPCServer(s.srv, s)
 
 	go func() {
-		err = s.srv.Serve(listen)
+		err := s.srv.Serve(listen)
 		if err != nil {
 			Log.Sugar().Infof("Remote server exit: %v", err)
 		}
 	}()
+	return nil
 }
 
+// ReloadTLS loads the certificate files given right away, new connections
+// use them. The running certificates are kept when the files fail to load.
+func (s *Server) ReloadTLS(certFile string, keyFile string, caFile string) error {
+	if s.certReloader == nil {
+		return errors.New("remote: server does not serve tls")
+	}
+	return s.certReloader.reset(certFile, keyFile, caFile)
+}
+
+// Addr returns the address the server listens on, it is only valid after
+// Start.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Stop closes every stream right away, cutting off the requests in flight.
 func (s *Server) Stop() {
 	s.srv.Stop()
+	s.release()
 	Log.Info("Remote server stopped")
 }
 
+// Shutdown stops taking connections and pushes NOTIFY_SHUTDOWN to every
+// stream. Streams end once their client closes its side and their requests
+// in flight are answered. When ctx is done first, the streams left are
+// closed like Stop and ctx.Err() is returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		close(s.shutdownChan)
+	})
+
+	err := waitContext(ctx, s.srv.GracefulStop)
+	if err != nil {
+		Log.Warn("Remote server shutdown timeout, close the streams left", zap.Error(err))
+		s.srv.Stop()
+	}
+	if err := waitContext(ctx, s.release); err != nil {
+		Log.Warn("Remote server shutdown timeout, executors still running", zap.Error(err))
+		return err
+	}
+	Log.Info("Remote server stopped")
+	return err
+}
+
+// waitContext runs f and waits for it to return until ctx is done.
+func waitContext(ctx context.Context, f func()) error {
+	done := make(chan struct{})
+	go func() {
+		f()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Server) release() {
+	if s.certReloader != nil {
+		s.certReloader.stop()
+	}
+	s.shutdownExecutors()
+}
+
+func (s *Server) shutdownExecutors() {
+	executors := make(map[*Executor]bool)
+	if s.defaultProcessor.executor != nil {
+		executors[s.defaultProcessor.executor] = true
+	}
+	for _, pair := range s.processorTable {
+		if pair.executor != nil {
+			executors[pair.executor] = true
+		}
+	}
+	for executor := range executors {
+		executor.Shutdown()
+	}
+}
+
 func (s *Server) Process(stream pb.RemoteRPC_ProcessServer) error {
 	ctx := stream.Context()
+	addr := GetRemoteAddr(ctx)
+	ch := newStreamChannel(addr, stream)
+	ctx, cancel := context.WithCancel(WithChannel(ctx, ch))
+
+	// Requests still running hold the stream, which can not be written
+	// once Process returns
+	var inFlight sync.WaitGroup
+	defer inFlight.Wait()
+	defer cancel()
+
+	reqChan := make(chan *pb.RemoteCommand)
+	errChan := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				errChan <- err
+				return
+			}
+			select {
+			case reqChan <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var idleCheck <-chan time.Time
+	if s.ChannelMaxIdleTime > 0 {
+		ticker := time.NewTicker(s.ChannelMaxIdleTime / 2)
+		defer ticker.Stop()
+		idleCheck = ticker.C
+	}
+
+	shutdown := s.shutdownChan
 	for {
-		req, err := stream.Recv()
-		if err != nil {
+		select {
+		case <-shutdown:
+			// Keep serving until the client closes its side, requests it
+			// sent before the notice must still be answered
+			shutdown = nil
+			notice := &pb.RemoteCommand{Code: int32(pb.RequestCode_NOTIFY_SHUTDOWN)}
+			MarkOnewayRPC(notice)
+			if err := ch.Send(notice); err != nil {
+				s.channelException(addr, err)
+				return nil
+			}
+		case req := <-reqChan:
+			ch.touch()
+			if IsResponseType(req) {
+				Log.Warn("Receive response, no request waiting for it",
+					zap.String("addr", addr),
+					zap.Int32("opaque", req.Opaque))
+				continue
+			}
+
+			if err := s.Dispatch(ctx, ch, req, &inFlight); err != nil {
+				s.channelException(addr, err)
+				return nil
+			}
+		case err := <-errChan:
 			if err == io.EOF {
 				return nil
 			}
+			s.channelException(addr, err)
 			return err
+		case <-idleCheck:
+			if ch.idleMills() >= s.ChannelMaxIdleTime.Milliseconds() {
+				Log.Warn("Channel idle, close it", zap.String("addr", addr))
+				if s.ChannelEventListener != nil {
+					s.ChannelEventListener.OnChannelIdle(addr)
+				}
+				return status.Error(codes.Unavailable, "channel idle")
+			}
 		}
+	}
+}
 
-		resp := s.Processor(ctx, req)
-		if resp == nil {
-			continue
-		}
+// Dispatch runs req on the executor of its code and sends the response to
+// ch, it answers SYSTEM_BUSY right away when the executor queue is full.
+// inFlight counts the requests still running. Other listeners use it to
+// share the processors, executors and interceptors of s.
+func (s *Server) Dispatch(ctx context.Context, ch Channel, req *pb.RemoteCommand, inFlight *sync.WaitGroup) error {
+	pair := s.processorOf(req.Code)
+	if pair.processor == nil {
+		Log.Warn("No processor for request", zap.Int32("code", req.Code))
+		return s.reply(ch, req, CreateResponseCommand(pb.ResponseCode_REQUEST_CODE_NOT_SUPPORTED,
+			"request code not supported"))
+	}
 
-		if err = stream.Send(resp); err != nil {
-			return nil
+	inFlight.Add(1)
+	submitted := pair.executor.Submit(func() {
+		defer inFlight.Done()
+		if err := s.reply(ch, req, pair.processor(ctx, req)); err != nil {
+			s.channelException(ch.RemoteAddr(), err)
 		}
+	})
+	if submitted {
+		return nil
+	}
+
+	inFlight.Done()
+	Log.Warn("Too many requests, executor queue is full",
+		zap.String("executor", pair.executor.Name()),
+		zap.String("addr", ch.RemoteAddr()),
+		zap.Int32("code", req.Code))
+	return s.reply(ch, req, CreateResponseCommand(pb.ResponseCode_SYSTEM_BUSY,
+		"[OVERLOAD]system busy, start flow control for a while"))
+}
+
+func (s *Server) reply(ch Channel, req *pb.RemoteCommand, resp *pb.RemoteCommand) error {
+	if resp == nil || IsOnewayRPC(req) {
+		return nil
+	}
+	resp.Opaque = req.Opaque
+	MarkResponseType(resp)
+	return ch.Send(resp)
+}
+
+func (s *Server) channelException(addr string, err error) {
+	if status.Code(err) == codes.Canceled {
+		return
+	}
+	Log.Warn("Channel exception", zap.String("addr", addr), zap.Error(err))
+	if s.ChannelEventListener != nil {
+		s.ChannelEventListener.OnChannelException(addr, err)
 	}
 }
 
@@ -87,6 +379,11 @@ func (s *Server) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.C
 	return context.WithValue(ctx, "conn", info)
 }
 
+// ActiveConnections returns the number of gRPC connections open.
+func (s *Server) ActiveConnections() int64 {
+	return s.activeConns.Load()
+}
+
 func (s *Server) HandleConn(ctx context.Context, state stats.ConnStats) {
 	info, ok := ctx.Value("conn").(*stats.ConnTagInfo)
 	if !ok {
@@ -98,9 +395,17 @@ func (s *Server) HandleConn(ctx context.Context, state stats.ConnStats) {
 
 	switch state.(type) {
 	case *stats.ConnBegin:
+		s.activeConns.Inc()
 		Log.Sugar().Infof("Connected, addr: %s", addr)
+		if s.ChannelEventListener != nil {
+			s.ChannelEventListener.OnChannelConnect(addr)
+		}
 	case *stats.ConnEnd:
+		s.activeConns.Dec()
 		Log.Sugar().Infof("Closed, addr: %s", info.RemoteAddr.String())
+		if s.ChannelEventListener != nil {
+			s.ChannelEventListener.OnChannelClose(addr)
+		}
 	default:
 		Log.Sugar().Errorf("Unknown type: %#v", info)
 	}
@@ -0,0 +1,172 @@
+package remote
+
+import (
+	"context"
+	pb "rocketmq-go/common/proto"
+	"testing"
+	"time"
+)
+
+func TestSlowCodeDoesNotBlockOthers(t *testing.T) {
+	release := make(chan struct{})
+
+	s := NewServer("127.0.0.1:0")
+	s.RegisterDefaultProcessor(echoProcessor, nil)
+	s.RegisterProcessor(pb.RequestCode_REGISTER_BROKER, func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		<-release
+		return echoProcessor(ctx, request)
+	}, NewExecutor("BrokerExecutor", 1, 1))
+	s.Start()
+	defer s.Stop()
+	defer close(release)
+
+	c := NewClient(s.Addr().String())
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	if err := c.InvokeAsync(&pb.RemoteCommand{Code: int32(pb.RequestCode_REGISTER_BROKER)}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	request := &pb.RemoteCommand{Code: int32(pb.RequestCode_GET_ROUTEINFO_BY_TOPIC), Remark: "route"}
+	response, err := c.InvokeSync(context.Background(), request, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Remark != "route" {
+		t.Fatalf("response remark = %s", response.Remark)
+	}
+}
+
+func TestExecutorFullRepliesSystemBusy(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	s := NewServer("127.0.0.1:0")
+	s.RegisterDefaultProcessor(func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		started <- struct{}{}
+		<-release
+		return echoProcessor(ctx, request)
+	}, NewExecutor("DefaultExecutor", 1, 1))
+	s.Start()
+	defer s.Stop()
+	defer close(release)
+
+	c := NewClient(s.Addr().String())
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	// The first request occupies the worker, the second fills the queue
+	for i := 0; i < 2; i++ {
+		if err := c.InvokeAsync(&pb.RemoteCommand{}, nil); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			<-started
+		}
+	}
+
+	response, err := c.InvokeSync(context.Background(), &pb.RemoteCommand{}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Code != int32(pb.ResponseCode_SYSTEM_BUSY) {
+		t.Fatalf("code = %d, want SYSTEM_BUSY", response.Code)
+	}
+}
+
+func TestShutdownDrainsInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	s1 := NewServer("127.0.0.1:0")
+	s1.RegisterDefaultProcessor(echoProcessor, nil)
+	s1.RegisterProcessor(pb.RequestCode_REGISTER_BROKER, func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		close(started)
+		<-release
+		return echoProcessor(ctx, request)
+	}, nil)
+	s1.Start()
+
+	s2 := NewServer("127.0.0.1:0")
+	s2.RegisterDefaultProcessor(echoProcessor, nil)
+	s2.Start()
+	defer s2.Stop()
+
+	c := NewClient(s1.Addr().String() + ";" + s2.Addr().String())
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	futures := make(chan *ResponseFuture, 1)
+	request := &pb.RemoteCommand{Code: int32(pb.RequestCode_REGISTER_BROKER), Remark: "register"}
+	if err := c.InvokeAsync(request, func(f *ResponseFuture) { futures <- f }); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownErr <- s1.Shutdown(ctx)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	future := <-futures
+	if future.Err() != nil || future.ResponseCommand().Remark != "register" {
+		t.Fatalf("in flight request: response %v, err %v", future.ResponseCommand(), future.Err())
+	}
+	if err := <-shutdownErr; err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	response, err := c.InvokeSync(context.Background(), &pb.RemoteCommand{Remark: "route"}, 3*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Remark != "route" || c.Addr() != s2.Addr().String() {
+		t.Fatalf("after shutdown: response %v from %s", response, c.Addr())
+	}
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	s := NewServer("127.0.0.1:0")
+	s.RegisterDefaultProcessor(func(ctx context.Context, request *pb.RemoteCommand) *pb.RemoteCommand {
+		close(started)
+		<-release
+		return echoProcessor(ctx, request)
+	}, nil)
+	s.Start()
+
+	c := NewClient(s.Addr().String())
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	if err := c.InvokeAsync(&pb.RemoteCommand{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := s.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("shutdown err = %v, want deadline exceeded", err)
+	}
+	if cost := time.Since(start); cost > 2*time.Second {
+		t.Fatalf("shutdown took %v past its deadline", cost)
+	}
+}
@@ -1,6 +1,13 @@
 package remote
 
+import "context"
+
 type Service interface {
-	Start()
+	// Start starts the service, e.g. listens on its address.
+	Start() error
+	// Stop stops the service right away.
 	Stop()
-}
\ No newline at end of file
+	// Shutdown stops taking new work and waits for the work in progress
+	// until ctx is done.
+	Shutdown(ctx context.Context) error
+}
@@ -0,0 +1,265 @@
+package remote
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	. "rocketmq-go/logging"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCertReloadInterval = 10 * time.Second
+)
+
+// TLSConfig enables TLS on a Server or Client. The certificate files are
+// polled and reloaded when they change, new connections use the new ones.
+type TLSConfig struct {
+	// CertFile and KeyFile are the own certificate, a client may leave them
+	// empty when the server does not require client certificates.
+	CertFile string
+	KeyFile  string
+	// CAFile verifies the certificate of the other side, the system pool is
+	// used when it is empty.
+	CAFile string
+	// ClientAuth makes the server require and verify client certificates.
+	ClientAuth bool
+	// AllowedSubjects limits the peers to the certificates whose common name
+	// or subject is in the list, empty allows every verified certificate.
+	AllowedSubjects []string
+	// ServerName overrides the name a client verifies the server with.
+	ServerName string
+	// ReloadInterval is how often the files are checked for change, zero
+	// takes the default and a negative value disables reloading.
+	ReloadInterval time.Duration
+}
+
+// certReloader holds the current certificate and CA pool of a TLSConfig.
+type certReloader struct {
+	rw       sync.RWMutex
+	conf     *TLSConfig
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+	modTimes map[string]time.Time
+
+	stopOnce sync.Once
+	stopChan chan struct{}
+}
+
+func newCertReloader(conf *TLSConfig) (*certReloader, error) {
+	r := &certReloader{
+		conf:     conf,
+		modTimes: make(map[string]time.Time),
+		stopChan: make(chan struct{}),
+	}
+	if err := r.load(conf); err != nil {
+		return nil, err
+	}
+
+	interval := conf.ReloadInterval
+	if interval == 0 {
+		interval = defaultCertReloadInterval
+	}
+	if interval > 0 {
+		go r.watch(interval)
+	}
+	return r, nil
+}
+
+func (r *certReloader) config() *TLSConfig {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+	return r.conf
+}
+
+func filesOf(conf *TLSConfig) []string {
+	files := make([]string, 0, 3)
+	for _, file := range []string{conf.CertFile, conf.KeyFile, conf.CAFile} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// load loads the files of conf and makes conf the current one.
+func (r *certReloader) load(conf *TLSConfig) error {
+	modTimes := make(map[string]time.Time)
+	for _, file := range filesOf(conf) {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	var cert *tls.Certificate
+	if conf.CertFile != "" || conf.KeyFile != "" {
+		c, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return fmt.Errorf("load key pair: %w", err)
+		}
+		cert = &c
+	}
+
+	var caPool *x509.CertPool
+	if conf.CAFile != "" {
+		data, err := ioutil.ReadFile(conf.CAFile)
+		if err != nil {
+			return err
+		}
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("no certificate found in %s", conf.CAFile)
+		}
+	}
+
+	r.rw.Lock()
+	r.conf = conf
+	r.cert = cert
+	r.caPool = caPool
+	r.modTimes = modTimes
+	r.rw.Unlock()
+	return nil
+}
+
+// reset switches to other certificate files, the loaded certificates are
+// kept when the new files fail to load.
+func (r *certReloader) reset(certFile string, keyFile string, caFile string) error {
+	conf := *r.config()
+	conf.CertFile = certFile
+	conf.KeyFile = keyFile
+	conf.CAFile = caFile
+	if err := r.load(&conf); err != nil {
+		return err
+	}
+	Log.Info("Tls certificates reloaded", zap.Strings("files", filesOf(&conf)))
+	return nil
+}
+
+func (r *certReloader) changed() bool {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	for _, file := range filesOf(r.conf) {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false
+		}
+		if !info.ModTime().Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+// reloadIfChanged keeps the loaded certificates when the new files are
+// broken, e.g. caught in the middle of being replaced.
+func (r *certReloader) reloadIfChanged() {
+	if !r.changed() {
+		return
+	}
+	conf := r.config()
+	if err := r.load(conf); err != nil {
+		Log.Error("Reload tls certificates failed", zap.Error(err))
+		return
+	}
+	Log.Info("Tls certificates reloaded", zap.Strings("files", filesOf(conf)))
+}
+
+func (r *certReloader) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.reloadIfChanged()
+		case <-r.stopChan:
+			return
+		}
+	}
+}
+
+func (r *certReloader) stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopChan)
+	})
+}
+
+func (r *certReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+	return r.cert, r.caPool
+}
+
+// serverConfig picks up the current certificates on every handshake.
+func (r *certReloader) serverConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, caPool := r.current()
+			if cert == nil {
+				return nil, errors.New("no server certificate")
+			}
+
+			conf := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientCAs:    caPool,
+			}
+			if tlsConf := r.config(); tlsConf.ClientAuth {
+				conf.ClientAuth = tls.RequireAndVerifyClientCert
+				conf.VerifyPeerCertificate = verifySubjects(tlsConf.AllowedSubjects)
+			}
+			return conf, nil
+		},
+	}
+}
+
+// clientConfig is built for each connection, so a reload takes effect on
+// the next reconnect.
+func (r *certReloader) clientConfig() *tls.Config {
+	cert, caPool := r.current()
+	tlsConf := r.config()
+	conf := &tls.Config{
+		MinVersion:            tls.VersionTLS12,
+		RootCAs:               caPool,
+		ServerName:            tlsConf.ServerName,
+		VerifyPeerCertificate: verifySubjects(tlsConf.AllowedSubjects),
+	}
+	if cert != nil {
+		conf.Certificates = []tls.Certificate{*cert}
+	}
+	return conf
+}
+
+// verifySubjects checks the leaf of the verified chain against allowed.
+func verifySubjects(allowed []string) func([][]byte, [][]*x509.Certificate) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, subject := range allowed {
+		allowedSet[subject] = true
+	}
+
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		for _, chain := range verifiedChains {
+			if len(chain) == 0 {
+				continue
+			}
+			leaf := chain[0]
+			if allowedSet[leaf.Subject.CommonName] || allowedSet[leaf.Subject.String()] {
+				return nil
+			}
+		}
+		return errors.New("peer certificate subject not allowed")
+	}
+}
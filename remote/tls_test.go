@@ -0,0 +1,215 @@
+package remote
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	pb "rocketmq-go/common/proto"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	dir  string
+}
+
+func newTestCA(t *testing.T) *testCA {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	ca := &testCA{cert: cert, key: key, dir: dir}
+	writePEM(t, ca.path("ca.pem"), "CERTIFICATE", der)
+	return ca
+}
+
+func (ca *testCA) path(name string) string {
+	return filepath.Join(ca.dir, name)
+}
+
+// issue writes name.pem and name.key signed by the CA and returns their
+// paths.
+func (ca *testCA) issue(t *testing.T, name string, commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := ca.path(name+".pem"), ca.path(name+".key")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDer)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, file string, blockType string, der []byte) {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := ioutil.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func startTLSServer(t *testing.T, ca *testCA, allowedSubjects []string) *Server {
+	certFile, keyFile := ca.issue(t, "server", "namesrv")
+	s := NewServer("127.0.0.1:0")
+	s.RegisterDefaultProcessor(echoProcessor, nil)
+	s.TLS = &TLSConfig{
+		CertFile:        certFile,
+		KeyFile:         keyFile,
+		CAFile:          ca.path("ca.pem"),
+		ClientAuth:      true,
+		AllowedSubjects: allowedSubjects,
+	}
+	s.Start()
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func newTLSClient(t *testing.T, addr string, conf *TLSConfig) *Client {
+	conf.ServerName = "localhost"
+	c := NewClient(addr)
+	c.TLS = conf
+	c.ConnectTimeout = 300 * time.Millisecond
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Stop)
+	return c
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	s := startTLSServer(t, ca, []string{"broker-a"})
+
+	certFile, keyFile := ca.issue(t, "client", "broker-a")
+	c := newTLSClient(t, s.Addr().String(), &TLSConfig{
+		CertFile: certFile,
+		KeyFile:  keyFile,
+		CAFile:   ca.path("ca.pem"),
+	})
+
+	response, err := c.InvokeSync(context.Background(), &pb.RemoteCommand{Remark: "tls"}, 3*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Remark != "tls" {
+		t.Fatalf("response remark = %s", response.Remark)
+	}
+}
+
+func TestMutualTLSRejectsClient(t *testing.T) {
+	ca := newTestCA(t)
+	s := startTLSServer(t, ca, []string{"broker-a"})
+	certFile, keyFile := ca.issue(t, "client", "broker-b")
+
+	tests := map[string]*TLSConfig{
+		"no client certificate": {CAFile: ca.path("ca.pem")},
+		"subject not allowed":   {CertFile: certFile, KeyFile: keyFile, CAFile: ca.path("ca.pem")},
+	}
+	for name, conf := range tests {
+		c := newTLSClient(t, s.Addr().String(), conf)
+		if _, err := c.InvokeSync(context.Background(), &pb.RemoteCommand{}, time.Second); err == nil {
+			t.Errorf("%s: request succeeded", name)
+		}
+	}
+}
+
+func TestCertReload(t *testing.T) {
+	ca := newTestCA(t)
+	certFile, keyFile := ca.issue(t, "server", "old")
+	r, err := newCertReloader(&TLSConfig{CertFile: certFile, KeyFile: keyFile, ReloadInterval: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca.issue(t, "server", "new")
+	future := time.Now().Add(time.Minute)
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r.reloadIfChanged()
+
+	cert, _ := r.current()
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaf.Subject.CommonName != "new" {
+		t.Fatalf("common name = %s, want new", leaf.Subject.CommonName)
+	}
+}
+
+func TestCertReset(t *testing.T) {
+	ca := newTestCA(t)
+	certFile, keyFile := ca.issue(t, "server", "old")
+	r, err := newCertReloader(&TLSConfig{CertFile: certFile, KeyFile: keyFile, ReloadInterval: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newCertFile, newKeyFile := ca.issue(t, "other", "new")
+	if err := r.reset(newCertFile, newKeyFile, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.reset(ca.path("missing.pem"), newKeyFile, ""); err == nil {
+		t.Fatal("reset to a missing file succeeded")
+	}
+
+	cert, _ := r.current()
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaf.Subject.CommonName != "new" || r.config().CertFile != newCertFile {
+		t.Fatalf("common name = %s, cert file %s", leaf.Subject.CommonName, r.config().CertFile)
+	}
+}